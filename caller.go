@@ -0,0 +1,73 @@
+package logos
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Caller describes the source location of a log call.
+type Caller struct {
+	File     string
+	Line     int
+	Function string
+}
+
+// String returns the short form "file.go:line".
+func (c Caller) String() string {
+	return fmt.Sprintf("%s:%d", filepath.Base(c.File), c.Line)
+}
+
+// ShortFunction returns the function name without its package path, e.g. "Server.handle".
+// Pointer receivers are written like value receivers, so "(*Server).handle" is also "Server.handle".
+func (c Caller) ShortFunction() string {
+	name := c.Function
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.IndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+	if strings.HasPrefix(name, "(*") {
+		if i := strings.Index(name, ")."); i >= 0 {
+			name = name[2:i] + name[i+1:]
+		}
+	}
+	return name
+}
+
+// packageDir is the directory holding the logos sources. Frames from this directory
+// (other than tests) belong to the logger itself and are skipped when finding the caller.
+var packageDir string
+
+func init() {
+	if _, file, _, ok := runtime.Caller(0); ok {
+		packageDir = filepath.Dir(file)
+	}
+}
+
+// callerOf returns the first stack frame outside the logos package, or nil if none is found.
+func callerOf() *Caller {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		if !isLogosFrame(frame.File) {
+			return &Caller{
+				File:     frame.File,
+				Line:     frame.Line,
+				Function: frame.Function,
+			}
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+// isLogosFrame reports whether file is a non-test source file of this package.
+func isLogosFrame(file string) bool {
+	return filepath.Dir(file) == packageDir && !strings.HasSuffix(file, "_test.go")
+}
@@ -0,0 +1,76 @@
+package logos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_WithCaller(t *testing.T) {
+	keepDefaultLogger(t)
+	var captured *Caller
+	fmtr := formatterFunc(func(level Level, entry Entry) string {
+		captured = entry.Caller
+		return entry.Msg
+	})
+
+	log := NewLogger(LevelDebug, fmtr, &bytes.Buffer{})
+	log.Info("no caller")
+	assert.Nil(t, captured)
+
+	log = log.WithCaller(true)
+	log.Info("direct")
+	if assert.NotNil(t, captured) {
+		assert.Contains(t, captured.File, "caller_test.go")
+		assert.Contains(t, captured.Function, "TestLogger_WithCaller")
+	}
+
+	// Package-level functions and derived loggers report the same call site.
+	SetDefaultLogger(log)
+	Infof("via %s", "default logger")
+	if assert.NotNil(t, captured) {
+		assert.Contains(t, captured.File, "caller_test.go")
+	}
+
+	log.With("key", "value").Logf(LevelWarn, "derived")
+	if assert.NotNil(t, captured) {
+		assert.Contains(t, captured.Function, "TestLogger_WithCaller")
+	}
+}
+
+// formatterFunc adapts a function to the Formatter interface.
+type formatterFunc func(level Level, entry Entry) string
+
+func (f formatterFunc) Format(level Level, entry Entry) string {
+	return f(level, entry)
+}
+
+type callerServer struct{ log Logger }
+
+func (s *callerServer) handle() { s.log.Info("handled") }
+
+func TestCaller_ShortFunction(t *testing.T) {
+	var captured *Caller
+	fmtr := formatterFunc(func(level Level, entry Entry) string {
+		captured = entry.Caller
+		return entry.Msg
+	})
+	server := &callerServer{log: NewLogger(LevelInfo, fmtr, &bytes.Buffer{}).WithCaller(true)}
+	server.handle()
+	if assert.NotNil(t, captured) {
+		assert.Equal(t, "callerServer.handle", captured.ShortFunction())
+	}
+
+	tests := map[string]string{
+		"main.main":                                  "main",
+		"github.com/acme/api.(*Server).handle":       "Server.handle",
+		"github.com/acme/api.Server.handle":          "Server.handle",
+		"github.com/acme/api.(*Server).handle.func1": "Server.handle.func1",
+		"github.com/acme/api.(*List[...]).Push":      "List[...].Push",
+		"github.com/acme/api.handler.func2":          "handler.func2",
+	}
+	for function, want := range tests {
+		assert.Equal(t, want, Caller{Function: function}.ShortFunction(), function)
+	}
+}
@@ -0,0 +1,437 @@
+package logos
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goodblaster/errors"
+)
+
+// LoggerConfig describes a Logger and its outputs, typically decoded from a JSON document
+// by FromConfig. Settings at this level are defaults for every output; fields are merged,
+// with output fields winning, and redaction here applies to all outputs in addition to
+// their own.
+type LoggerConfig struct {
+	Level      string            `json:"level,omitempty"`       // Logger level. Defaults to "info".
+	Levels     map[string]string `json:"levels,omitempty"`      // Levels by logger name, which also cover dotted sub-names.
+	Name       string            `json:"name,omitempty"`        // Logger name, as set with Named.
+	Caller     bool              `json:"caller,omitempty"`      // Record the call site, as with WithCaller.
+	Theme      string            `json:"theme,omitempty"`       // Console theme; see LookupTheme.
+	TimeFormat string            `json:"time_format,omitempty"` // Go time layout, "rfc3339" or "rfc3339nano". Defaults to DefaultTimestampFormat.
+	TimeZone   string            `json:"time_zone,omitempty"`   // IANA time zone name, "UTC" or "Local". Defaults to local time.
+	Fields     Fields            `json:"fields,omitempty"`      // Static fields added to every entry.
+	Redact     *RedactConfig     `json:"redact,omitempty"`      // Redaction applied to every output.
+	Sampling   *SamplingConfig   `json:"sampling,omitempty"`    // Sampling for outputs without their own.
+	Outputs    []OutputConfig    `json:"outputs,omitempty"`     // Destinations. Defaults to console output on stdout.
+}
+
+// OutputConfig describes one destination of a LoggerConfig.
+type OutputConfig struct {
+	Format      string          `json:"format,omitempty"`      // A FormatNames entry (case-insensitive) or "pattern". Defaults to "console".
+	Pattern     string          `json:"pattern,omitempty"`     // Layout for the "pattern" format; see NewPatternFormatter.
+	Level       string          `json:"level,omitempty"`       // Replaces LoggerConfig.Level and Levels for this output.
+	Destination string          `json:"destination,omitempty"` // "stdout", "stderr", "file" or "network". Inferred from File or Network, else "stdout".
+	File        *FileConfig     `json:"file,omitempty"`
+	Network     *NetworkConfig  `json:"network,omitempty"`
+	Fields      Fields          `json:"fields,omitempty"`
+	Redact      *RedactConfig   `json:"redact,omitempty"`
+	Sampling    *SamplingConfig `json:"sampling,omitempty"`
+}
+
+// FileConfig configures a "file" destination; see FileOptions.
+type FileConfig struct {
+	Path       string `json:"path"`
+	MaxSizeMB  int    `json:"max_size_mb,omitempty"`
+	MaxBackups int    `json:"max_backups,omitempty"`
+	MaxAge     string `json:"max_age,omitempty"` // A time.ParseDuration string, such as "168h".
+	Compress   bool   `json:"compress,omitempty"`
+}
+
+// NetworkConfig configures a "network" destination; see NetworkOptions.
+type NetworkConfig struct {
+	Protocol string `json:"protocol"` // "tcp", "udp" or "unix".
+	Address  string `json:"address"`
+	Timeout  string `json:"timeout,omitempty"` // A time.ParseDuration string.
+}
+
+// RedactConfig configures a Redactor; see RedactorOptions.
+type RedactConfig struct {
+	Keys         []string `json:"keys,omitempty"`     // Defaults to DefaultRedactKeys.
+	Patterns     []string `json:"patterns,omitempty"` // Names of DefaultSecretPatterns entries. Defaults to all of them; [] disables them.
+	Mask         string   `json:"mask,omitempty"`
+	RevealPrefix int      `json:"reveal_prefix,omitempty"`
+	RevealSuffix int      `json:"reveal_suffix,omitempty"`
+}
+
+// SamplingConfig configures a sampler; see SamplerOptions.
+type SamplingConfig struct {
+	Tick       string `json:"tick,omitempty"` // A time.ParseDuration string. Defaults to "1s".
+	First      int    `json:"first,omitempty"`
+	Thereafter int    `json:"thereafter,omitempty"`
+}
+
+// FromConfig decodes a JSON LoggerConfig from r and builds it. Unknown keys are rejected so
+// that typos are reported instead of silently ignored.
+//
+//	{
+//	  "level": "info",
+//	  "fields": {"service": "api"},
+//	  "outputs": [
+//	    {"format": "json", "destination": "stdout"},
+//	    {"format": "text", "level": "debug", "file": {"path": "/var/log/api.log", "max_size_mb": 100}}
+//	  ]
+//	}
+func FromConfig(r io.Reader) (Logger, io.Closer, error) {
+	cfg, err := decodeConfig(r)
+	if err != nil {
+		return Logger{}, nil, err
+	}
+	return cfg.Build()
+}
+
+// decodeConfig decodes a JSON LoggerConfig, rejecting unknown keys.
+func decodeConfig(r io.Reader) (LoggerConfig, error) {
+	var cfg LoggerConfig
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&cfg); err != nil {
+		return LoggerConfig{}, errors.Wrap(err, "invalid logger config")
+	}
+	return cfg, nil
+}
+
+// Build validates the configuration and builds the Logger: the first output is the main
+// destination and the others receive the same entries, each filtering by its own level.
+// Loggers derived from it with With, Named and the like write through every output.
+// The returned Closer closes the files and connections opened for the outputs. Unknown
+// formats, levels and destinations are reported as errors naming the offending output.
+func (c LoggerConfig) Build() (Logger, io.Closer, error) {
+	state, closer, err := c.build()
+	if err != nil {
+		return Logger{}, nil, err
+	}
+	live := &liveConfig{}
+	live.state.Store(state)
+	return live.logger(c.Name), closer, nil
+}
+
+// build validates the configuration and creates its outputs.
+func (c LoggerConfig) build() (*liveState, io.Closer, error) {
+	state := &liveState{level: LevelInfo, caller: c.Caller, closed: make(chan struct{})}
+	if c.Level != "" {
+		level, err := ParseLevel(c.Level)
+		if err != nil {
+			return nil, nil, err
+		}
+		state.level = level
+	}
+	if len(c.Levels) > 0 {
+		state.levels = make(map[string]Level, len(c.Levels))
+		for name, value := range c.Levels {
+			level, err := ParseLevel(value)
+			if err != nil {
+				return nil, nil, errors.Wrap(err, "levels[%q]", name)
+			}
+			state.levels[name] = level
+		}
+	}
+
+	var redactor *Redactor
+	if c.Redact != nil {
+		var err error
+		if redactor, err = c.Redact.redactor(); err != nil {
+			return nil, nil, errors.Wrap(err, "redact")
+		}
+	}
+	cfg, err := c.formatterConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	outputs := c.Outputs
+	if len(outputs) == 0 {
+		outputs = []OutputConfig{{}}
+	}
+
+	closers := multiCloser{}
+	loggers := make([]Logger, 0, len(outputs))
+	for i, output := range outputs {
+		logger, closer, err := output.build(c, cfg)
+		if err != nil {
+			_ = closers.Close()
+			return nil, nil, errors.Wrap(err, "outputs[%d]", i)
+		}
+		if closer != nil {
+			closers = append(closers, closer)
+		}
+		loggers = append(loggers, logger)
+	}
+
+	state.root = loggers[0].Tee(loggers[1:]...)
+	if redactor != nil {
+		// Transformers of the main logger also apply to its tee loggers
+		state.root = state.root.WithRedactor(redactor)
+	}
+	return state, closers, nil
+}
+
+// build creates the logger for one output. Outputs without a level of their own use the
+// logger level, or the level configured for the logger's name, when an entry is written.
+func (o OutputConfig) build(c LoggerConfig, cfg Config) (Logger, io.Closer, error) {
+	level := levelUnset
+	if o.Level != "" {
+		var err error
+		if level, err = ParseLevel(o.Level); err != nil {
+			return Logger{}, nil, err
+		}
+	}
+
+	var sampler Sampler
+	sampling := o.Sampling
+	if sampling == nil {
+		sampling = c.Sampling
+	}
+	if sampling != nil {
+		var err error
+		if sampler, err = sampling.sampler(); err != nil {
+			return Logger{}, nil, errors.Wrap(err, "sampling")
+		}
+	}
+
+	var redactor *Redactor
+	if o.Redact != nil {
+		var err error
+		if redactor, err = o.Redact.redactor(); err != nil {
+			return Logger{}, nil, errors.Wrap(err, "redact")
+		}
+	}
+
+	writer, closer, err := o.writer()
+	if err != nil {
+		return Logger{}, nil, err
+	}
+	formatter, err := o.formatter(writer, cfg)
+	if err != nil {
+		if closer != nil {
+			_ = closer.Close()
+		}
+		return Logger{}, nil, err
+	}
+	if redactor != nil {
+		// Redact in the formatter so the redactor does not reach the other outputs,
+		// as it would as a transformer of the main logger
+		formatter = transformingFormatter{transformer: redactor, formatter: formatter}
+	}
+
+	logger := NewLogger(level, formatter, writer)
+	if len(c.Fields) > 0 {
+		logger = logger.WithFields(c.Fields)
+	}
+	if len(o.Fields) > 0 {
+		logger = logger.WithFields(o.Fields)
+	}
+	if sampler != nil {
+		logger = logger.WithSampler(sampler)
+	}
+	return logger, closer, nil
+}
+
+// writer opens the output's destination.
+func (o OutputConfig) writer() (io.Writer, io.Closer, error) {
+	destination := strings.ToLower(o.Destination)
+	if destination == "" {
+		switch {
+		case o.File != nil:
+			destination = "file"
+		case o.Network != nil:
+			destination = "network"
+		default:
+			destination = "stdout"
+		}
+	}
+
+	switch destination {
+	case "stdout":
+		return os.Stdout, nil, nil
+	case "stderr":
+		return os.Stderr, nil, nil
+	case "file":
+		if o.File == nil {
+			return nil, nil, errors.New("file destination requires a file section")
+		}
+		opts := FileOptions{
+			Path:       o.File.Path,
+			MaxSize:    int64(o.File.MaxSizeMB) * 1024 * 1024,
+			MaxBackups: o.File.MaxBackups,
+			Compress:   o.File.Compress,
+		}
+		var err error
+		if opts.MaxAge, err = parseConfigDuration(o.File.MaxAge); err != nil {
+			return nil, nil, errors.Wrap(err, "file max_age")
+		}
+		w, err := OpenFile(opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return w, w, nil
+	case "network":
+		if o.Network == nil {
+			return nil, nil, errors.New("network destination requires a network section")
+		}
+		opts := NetworkOptions{Network: o.Network.Protocol, Address: o.Network.Address}
+		var err error
+		if opts.Timeout, err = parseConfigDuration(o.Network.Timeout); err != nil {
+			return nil, nil, errors.Wrap(err, "network timeout")
+		}
+		w, err := NewNetworkWriter(opts)
+		if err != nil {
+			return nil, nil, err
+		}
+		return w, w, nil
+	}
+	return nil, nil, errors.New("unknown destination %q", o.Destination)
+}
+
+// formatter creates the output's formatter. Console output falls back to plain text when
+// the destination is not a terminal.
+func (o OutputConfig) formatter(w io.Writer, cfg Config) (Formatter, error) {
+	if strings.EqualFold(o.Format, "pattern") {
+		if o.Pattern == "" {
+			return nil, errors.New("pattern format requires a pattern")
+		}
+		return NewPatternFormatter(o.Pattern, cfg)
+	}
+	if o.Format == "" {
+		return NewAutoConsoleFormatter(w, cfg), nil
+	}
+
+	format, err := ParseFormat(o.Format)
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatConsole {
+		return NewAutoConsoleFormatter(w, cfg), nil
+	}
+	return NewFormatterWithConfig(format, cfg), nil
+}
+
+// formatterConfig returns DefaultConfig with the configured theme and timestamps.
+func (c LoggerConfig) formatterConfig() (Config, error) {
+	cfg := DefaultConfig
+	if c.Theme != "" {
+		theme, ok := LookupTheme(c.Theme)
+		if !ok {
+			return cfg, errors.New("unknown theme %q", c.Theme)
+		}
+		cfg.Theme = &theme
+	}
+	if c.TimeFormat == "" && c.TimeZone == "" {
+		return cfg, nil
+	}
+
+	layout := DefaultTimestampFormat
+	switch strings.ToLower(c.TimeFormat) {
+	case "":
+	case "rfc3339":
+		layout = time.RFC3339
+	case "rfc3339nano":
+		layout = time.RFC3339Nano
+	default:
+		layout = c.TimeFormat
+	}
+	location := time.Local
+	if c.TimeZone != "" {
+		var err error
+		if location, err = time.LoadLocation(c.TimeZone); err != nil {
+			return cfg, errors.Wrap(err, "invalid time zone %q", c.TimeZone)
+		}
+	}
+	cfg.Timestamp = func() string {
+		return time.Now().In(location).Format(layout)
+	}
+	return cfg, nil
+}
+
+// redactor creates the configured Redactor.
+func (r RedactConfig) redactor() (*Redactor, error) {
+	opts := RedactorOptions{
+		Keys:         r.Keys,
+		Mask:         r.Mask,
+		RevealPrefix: r.RevealPrefix,
+		RevealSuffix: r.RevealSuffix,
+	}
+	if r.Patterns != nil {
+		opts.Patterns = make([]SecretPattern, 0, len(r.Patterns))
+	}
+	for _, name := range r.Patterns {
+		pattern, ok := lookupSecretPattern(name)
+		if !ok {
+			return nil, errors.New("unknown secret pattern %q", name)
+		}
+		opts.Patterns = append(opts.Patterns, pattern)
+	}
+	return NewRedactor(opts), nil
+}
+
+// sampler creates the configured Sampler.
+func (s SamplingConfig) sampler() (Sampler, error) {
+	tick, err := parseConfigDuration(s.Tick)
+	if err != nil {
+		return nil, errors.Wrap(err, "tick")
+	}
+	if s.First < 0 || s.Thereafter < 0 || (s.First == 0 && s.Thereafter == 0) {
+		return nil, errors.New("first and thereafter must not be negative, and one must be positive")
+	}
+	return NewSampler(SamplerOptions{Tick: tick, First: s.First, Thereafter: s.Thereafter}), nil
+}
+
+// lookupSecretPattern returns the entry of DefaultSecretPatterns with the given name.
+func lookupSecretPattern(name string) (SecretPattern, bool) {
+	for _, pattern := range DefaultSecretPatterns {
+		if strings.EqualFold(pattern.Name, name) {
+			return pattern, true
+		}
+	}
+	return SecretPattern{}, false
+}
+
+// parseConfigDuration parses an optional duration string.
+func parseConfigDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid duration %q", s)
+	}
+	if d < 0 {
+		return 0, errors.New("duration %q must not be negative", s)
+	}
+	return d, nil
+}
+
+// transformingFormatter applies a transformer to entries of a single output.
+type transformingFormatter struct {
+	transformer Transformer
+	formatter   Formatter
+}
+
+func (f transformingFormatter) Format(level Level, entry Entry) string {
+	return f.formatter.Format(level, f.transformer.Transform(level, entry))
+}
+
+// multiCloser closes several closers, returning the first error.
+type multiCloser []io.Closer
+
+func (m multiCloser) Close() error {
+	var first error
+	for _, closer := range m {
+		if err := closer.Close(); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
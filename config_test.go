@@ -0,0 +1,152 @@
+package logos
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readLines(t *testing.T, path string) []map[string]any {
+	t.Helper()
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(string(b)), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(line), &m), line)
+		out = append(out, m)
+	}
+	return out
+}
+
+func TestFromConfig(t *testing.T) {
+	dir := t.TempDir()
+	doc := `{
+		"level": "info",
+		"name": "api",
+		"fields": {"service": "api", "env": "prod"},
+		"redact": {"keys": ["password"], "patterns": []},
+		"outputs": [
+			{"format": "json", "file": {"path": "` + filepath.Join(dir, "main.log") + `"}},
+			{"format": "JSON", "level": "debug", "fields": {"env": "debug"},
+			 "redact": {"keys": ["user"]},
+			 "destination": "file", "file": {"path": "` + filepath.Join(dir, "debug.log") + `"}}
+		]
+	}`
+
+	log, closer, err := FromConfig(strings.NewReader(doc))
+	assert.NoError(t, err)
+	log.Debug("verbose")
+	// Fields added with With reach every output, as do context fields
+	ctx := AddFields(context.Background(), Fields{"password": "hunter2"})
+	log.With("user", "alice").InfoContext(ctx, "login")
+	assert.NoError(t, closer.Close())
+
+	main := readLines(t, filepath.Join(dir, "main.log"))
+	assert.Len(t, main, 1)
+	assert.Equal(t, "login", main[0]["msg"])
+	fields := main[0]["fields"].(map[string]any)
+	assert.Equal(t, "api", fields["service"])
+	assert.Equal(t, "prod", fields["env"])
+	assert.Equal(t, DefaultRedactMask, fields["password"])
+	assert.Equal(t, "alice", fields["user"]) // The other output's redaction does not apply
+
+	debug := readLines(t, filepath.Join(dir, "debug.log"))
+	assert.Len(t, debug, 2)
+	assert.Equal(t, "verbose", debug[0]["msg"])
+	fields = debug[1]["fields"].(map[string]any)
+	assert.Equal(t, "debug", fields["env"])
+	assert.Equal(t, DefaultRedactMask, fields["password"])
+	assert.Equal(t, DefaultRedactMask, fields["user"])
+}
+
+func TestFromConfig_Errors(t *testing.T) {
+	tests := map[string]string{
+		`{"level": "loud"}`:                                      `unknown level "loud"`,
+		`{"outputs": [{}, {"format": "xml"}]}`:                   `unknown format "xml"`,
+		`{"outputs": [{"level": "verbose"}]}`:                    `unknown level "verbose"`,
+		`{"outputs": [{"destination": "kafka"}]}`:                `unknown destination "kafka"`,
+		`{"outputs": [{"destination": "file"}]}`:                 `requires a file section`,
+		`{"outputs": [{"format": "pattern"}]}`:                   `requires a pattern`,
+		`{"outputs": [{"network": {"protocol": "smtp"}}]}`:       `unsupported network "smtp"`,
+		`{"outputs": [{"file": {"path": "x", "max_age": "1"}}]}`: `invalid duration "1"`,
+		`{"sampling": {"first": 0}}`:                             `one must be positive`,
+		`{"redact": {"patterns": ["ssn"]}}`:                      `unknown secret pattern "ssn"`,
+		`{"outputs": [{"fromat": "json"}]}`:                      `unknown field "fromat"`,
+		`{"levels": {"db": "chatty"}}`:                           `unknown level "chatty"`,
+		`{"theme": "neon"}`:                                      `unknown theme "neon"`,
+		`{"time_zone": "Mars/Olympus"}`:                          `invalid time zone "Mars/Olympus"`,
+	}
+	for doc, want := range tests {
+		_, _, err := FromConfig(strings.NewReader(doc))
+		if assert.Error(t, err, doc) {
+			assert.Contains(t, err.Error(), want, doc)
+		}
+	}
+
+	_, _, err := FromConfig(strings.NewReader(`{"outputs": [{}, {"format": "xml"}]}`))
+	assert.Contains(t, err.Error(), "outputs[1]")
+}
+
+func TestLoggerConfig_Build(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	cfg := LoggerConfig{
+		Outputs: []OutputConfig{{
+			Format:   "pattern",
+			Pattern:  "%{level} %{msg}",
+			File:     &FileConfig{Path: path},
+			Sampling: &SamplingConfig{First: 2},
+		}},
+	}
+
+	log, closer, err := cfg.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, LevelInfo, log.GetLevel())
+	for i := 0; i < 5; i++ {
+		log.Info("repeated")
+	}
+	log.Print("always")
+	assert.NoError(t, closer.Close())
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "info repeated\ninfo repeated\nprint always\n", string(b))
+}
+
+func TestLoggerConfig_Levels(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	cfg := LoggerConfig{
+		Level:  "warn",
+		Levels: map[string]string{"db": "debug", "db.pool": "error"},
+		Outputs: []OutputConfig{
+			{Format: "pattern", Pattern: "%{level} %{logger} %{msg}", File: &FileConfig{Path: path}},
+		},
+	}
+
+	log, closer, err := cfg.Build()
+	assert.NoError(t, err)
+	db := log.Named("db")
+	assert.Equal(t, LevelWarn, log.GetLevel())
+	assert.Equal(t, LevelDebug, db.GetLevel())
+	assert.Equal(t, LevelError, db.Named("pool").GetLevel())
+	assert.True(t, db.Named("query").IsLevelEnabled(LevelDebug))
+	assert.False(t, log.IsLevelEnabled(LevelInfo))
+
+	log.Info("dropped")
+	db.Debug("query")
+	db.Named("pool").Warn("dropped")
+	db.WithLevel(LevelError).Warn("dropped") // A level set on the logger wins
+	assert.NoError(t, closer.Close())
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "debug db query\n", string(b))
+}
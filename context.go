@@ -2,6 +2,8 @@ package logos
 
 import (
 	"context"
+	"fmt"
+	"sync"
 )
 
 type contextKey string
@@ -11,6 +13,8 @@ const CtxKeyLogger contextKey = "logos.logger"
 
 // FromContext retrieves a Logger from the context. If a logger is found in the context,
 // it returns that logger. Otherwise, it returns the default logger.
+// Either way, a level override stored with WithLevelOverride is applied, and the trace
+// fields of a TraceContext stored with WithTraceContext are added.
 func FromContext(ctx context.Context) Logger {
 	if ctx == nil {
 		return getDefaultLogger()
@@ -18,13 +22,13 @@ func FromContext(ctx context.Context) Logger {
 
 	if ctxLog, ok := ctx.Value(CtxKeyLogger).(Logger); ok {
 		// Validate that the logger has all required fields
-		if ctxLog.level != nil && ctxLog.formatter != nil && ctxLog.writer != nil {
-			return ctxLog
+		if ctxLog.level != nil && (ctxLog.live != nil || ctxLog.formatter != nil && ctxLog.writer != nil) {
+			return ctxLog.withContextLevel(ctx).withTraceFields(ctx)
 		}
 	}
 
 	// Fall back to default logger
-	return getDefaultLogger()
+	return getDefaultLogger().withContextLevel(ctx).withTraceFields(ctx)
 }
 
 // WithLogger returns a new context with the provided logger stored in it.
@@ -35,3 +39,349 @@ func WithLogger(ctx context.Context, logger Logger) context.Context {
 	}
 	return context.WithValue(ctx, CtxKeyLogger, logger)
 }
+
+// CtxKeyFields is the key used to store fields added with AddFields in context.Context.
+const CtxKeyFields contextKey = "logos.fields"
+
+// ContextExtractor returns fields derived from values stored in a context, such as a
+// request ID or a tenant. It is called for every entry logged with a *Context method and
+// should return nil if the context holds nothing of interest.
+type ContextExtractor func(ctx context.Context) Fields
+
+var (
+	contextExtractors   []ContextExtractor
+	contextExtractorsMu sync.RWMutex
+)
+
+// RegisterContextExtractor adds an extractor whose fields are added to every entry logged
+// with a *Context method, by any logger including the default logger. Extractors run in
+// registration order, and later ones win when keys collide.
+// This function is thread-safe.
+func RegisterContextExtractor(extractor ContextExtractor) {
+	contextExtractorsMu.Lock()
+	defer contextExtractorsMu.Unlock()
+	contextExtractors = append(contextExtractors, extractor)
+}
+
+// ContextValueExtractor returns an extractor that adds ctx.Value(key) under field when it is set.
+func ContextValueExtractor(key any, field string) ContextExtractor {
+	return func(ctx context.Context) Fields {
+		if value := ctx.Value(key); value != nil {
+			return Fields{field: value}
+		}
+		return nil
+	}
+}
+
+// AddFields returns a new context carrying fields, in addition to any added before, that
+// are logged by every *Context method called with it. No Logger is needed.
+func AddFields(ctx context.Context, fields Fields) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	existing, _ := ctx.Value(CtxKeyFields).(Fields)
+	merged := make(Fields, len(existing)+len(fields))
+	for key, value := range existing {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return context.WithValue(ctx, CtxKeyFields, merged)
+}
+
+// ContextFields returns the fields a *Context method would add for ctx: the trace fields of
+// its TraceContext, those from the registered extractors, then those added with AddFields.
+func ContextFields(ctx context.Context) Fields {
+	if ctx == nil {
+		return nil
+	}
+
+	contextExtractorsMu.RLock()
+	extractors := contextExtractors
+	contextExtractorsMu.RUnlock()
+
+	var fields Fields
+	add := func(extra Fields) {
+		if len(extra) == 0 {
+			return
+		}
+		if fields == nil {
+			fields = make(Fields, len(extra))
+		}
+		for key, value := range extra {
+			fields[key] = value
+		}
+	}
+	if tc, ok := TraceContextFromContext(ctx); ok {
+		add(tc.Fields())
+	}
+	for _, extractor := range extractors {
+		add(extractor(ctx))
+	}
+	if added, ok := ctx.Value(CtxKeyFields).(Fields); ok {
+		add(added)
+	}
+	return fields
+}
+
+// LogContext logs a message at the specified level, adding the context's fields
+// (see ContextFields) to the entry written by this logger and each tee logger.
+// The logger's own fields take precedence over context fields with the same key.
+// A level override stored with WithLevelOverride replaces the logger's level.
+func (logger Logger) LogContext(ctx context.Context, level Level, a ...any) {
+	logger = logger.withContextLevel(ctx)
+	if !logger.anyEnabled(level) {
+		return
+	}
+	logger.dispatch(level, fmt.Sprint(a...), ContextFields(ctx), nil)
+}
+
+// LogfContext logs a formatted message at the specified level with the context's fields.
+func (logger Logger) LogfContext(ctx context.Context, level Level, format string, args ...any) {
+	logger = logger.withContextLevel(ctx)
+	if !logger.anyEnabled(level) {
+		return
+	}
+	logger.dispatch(level, fmt.Sprintf(format, args...), ContextFields(ctx), nil)
+}
+
+// PrintContext logs a message at the print level with the context's fields.
+func (logger Logger) PrintContext(ctx context.Context, a ...any) {
+	logger.LogContext(ctx, LevelPrint, a...)
+}
+
+// PrintfContext logs a formatted message at the print level with the context's fields.
+func (logger Logger) PrintfContext(ctx context.Context, format string, args ...any) {
+	logger.LogfContext(ctx, LevelPrint, format, args...)
+}
+
+// DebugContext logs a message at the debug level with the context's fields.
+func (logger Logger) DebugContext(ctx context.Context, a ...any) {
+	logger.LogContext(ctx, LevelDebug, a...)
+}
+
+// DebugfContext logs a formatted message at the debug level with the context's fields.
+func (logger Logger) DebugfContext(ctx context.Context, format string, args ...any) {
+	logger.LogfContext(ctx, LevelDebug, format, args...)
+}
+
+// InfoContext logs a message at the info level with the context's fields.
+func (logger Logger) InfoContext(ctx context.Context, a ...any) {
+	logger.LogContext(ctx, LevelInfo, a...)
+}
+
+// InfofContext logs a formatted message at the info level with the context's fields.
+func (logger Logger) InfofContext(ctx context.Context, format string, args ...any) {
+	logger.LogfContext(ctx, LevelInfo, format, args...)
+}
+
+// WarnContext logs a message at the warn level with the context's fields.
+func (logger Logger) WarnContext(ctx context.Context, a ...any) {
+	logger.LogContext(ctx, LevelWarn, a...)
+}
+
+// WarnfContext logs a formatted message at the warn level with the context's fields.
+func (logger Logger) WarnfContext(ctx context.Context, format string, args ...any) {
+	logger.LogfContext(ctx, LevelWarn, format, args...)
+}
+
+// ErrorContext logs a message at the error level with the context's fields.
+func (logger Logger) ErrorContext(ctx context.Context, a ...any) {
+	logger.LogContext(ctx, LevelError, a...)
+}
+
+// ErrorfContext logs a formatted message at the error level with the context's fields.
+func (logger Logger) ErrorfContext(ctx context.Context, format string, args ...any) {
+	logger.LogfContext(ctx, LevelError, format, args...)
+}
+
+// FatalContext logs a message at the fatal level with the context's fields and then panics.
+func (logger Logger) FatalContext(ctx context.Context, a ...any) {
+	logger.LogContext(ctx, LevelFatal, a...)
+	panic(fmt.Sprint(a...))
+}
+
+// FatalfContext logs a formatted message at the fatal level with the context's fields and then panics.
+func (logger Logger) FatalfContext(ctx context.Context, format string, args ...any) {
+	logger.LogfContext(ctx, LevelFatal, format, args...)
+	panic(fmt.Sprintf(format, args...))
+}
+
+// TraceContext logs a message at the trace level with the context's fields.
+func (logger Logger) TraceContext(ctx context.Context, a ...any) {
+	logger.LogContext(ctx, LevelTrace, a...)
+}
+
+// TracefContext logs a formatted message at the trace level with the context's fields.
+func (logger Logger) TracefContext(ctx context.Context, format string, args ...any) {
+	logger.LogfContext(ctx, LevelTrace, format, args...)
+}
+
+// NoticeContext logs a message at the notice level with the context's fields.
+func (logger Logger) NoticeContext(ctx context.Context, a ...any) {
+	logger.LogContext(ctx, LevelNotice, a...)
+}
+
+// NoticefContext logs a formatted message at the notice level with the context's fields.
+func (logger Logger) NoticefContext(ctx context.Context, format string, args ...any) {
+	logger.LogfContext(ctx, LevelNotice, format, args...)
+}
+
+// CriticalContext logs a message at the critical level with the context's fields.
+func (logger Logger) CriticalContext(ctx context.Context, a ...any) {
+	logger.LogContext(ctx, LevelCritical, a...)
+}
+
+// CriticalfContext logs a formatted message at the critical level with the context's fields.
+func (logger Logger) CriticalfContext(ctx context.Context, format string, args ...any) {
+	logger.LogfContext(ctx, LevelCritical, format, args...)
+}
+
+// AlertContext logs a message at the alert level with the context's fields.
+func (logger Logger) AlertContext(ctx context.Context, a ...any) {
+	logger.LogContext(ctx, LevelAlert, a...)
+}
+
+// AlertfContext logs a formatted message at the alert level with the context's fields.
+func (logger Logger) AlertfContext(ctx context.Context, format string, args ...any) {
+	logger.LogfContext(ctx, LevelAlert, format, args...)
+}
+
+// EmergencyContext logs a message at the emergency level with the context's fields.
+func (logger Logger) EmergencyContext(ctx context.Context, a ...any) {
+	logger.LogContext(ctx, LevelEmergency, a...)
+}
+
+// EmergencyfContext logs a formatted message at the emergency level with the context's fields.
+func (logger Logger) EmergencyfContext(ctx context.Context, format string, args ...any) {
+	logger.LogfContext(ctx, LevelEmergency, format, args...)
+}
+
+// PanicContext logs a message at the panic level with the context's fields and then panics.
+func (logger Logger) PanicContext(ctx context.Context, a ...any) {
+	logger.LogContext(ctx, LevelPanic, a...)
+	panic(fmt.Sprint(a...))
+}
+
+// PanicfContext logs a formatted message at the panic level with the context's fields and then panics.
+func (logger Logger) PanicfContext(ctx context.Context, format string, args ...any) {
+	logger.LogfContext(ctx, LevelPanic, format, args...)
+	panic(fmt.Sprintf(format, args...))
+}
+
+// LogContext logs a message at the specified level using the context's logger (see FromContext)
+// and the context's fields.
+func LogContext(ctx context.Context, level Level, a ...any) {
+	FromContext(ctx).LogContext(ctx, level, a...)
+}
+
+// PrintContext logs a message at the print level using the context's logger and fields.
+func PrintContext(ctx context.Context, a ...any) {
+	FromContext(ctx).PrintContext(ctx, a...)
+}
+
+// PrintfContext logs a formatted message at the print level using the context's logger and fields.
+func PrintfContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).PrintfContext(ctx, format, args...)
+}
+
+// DebugContext logs a message at the debug level using the context's logger and fields.
+func DebugContext(ctx context.Context, a ...any) {
+	FromContext(ctx).DebugContext(ctx, a...)
+}
+
+// DebugfContext logs a formatted message at the debug level using the context's logger and fields.
+func DebugfContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).DebugfContext(ctx, format, args...)
+}
+
+// InfoContext logs a message at the info level using the context's logger and fields.
+func InfoContext(ctx context.Context, a ...any) {
+	FromContext(ctx).InfoContext(ctx, a...)
+}
+
+// InfofContext logs a formatted message at the info level using the context's logger and fields.
+func InfofContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).InfofContext(ctx, format, args...)
+}
+
+// WarnContext logs a message at the warn level using the context's logger and fields.
+func WarnContext(ctx context.Context, a ...any) {
+	FromContext(ctx).WarnContext(ctx, a...)
+}
+
+// WarnfContext logs a formatted message at the warn level using the context's logger and fields.
+func WarnfContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).WarnfContext(ctx, format, args...)
+}
+
+// ErrorContext logs a message at the error level using the context's logger and fields.
+func ErrorContext(ctx context.Context, a ...any) {
+	FromContext(ctx).ErrorContext(ctx, a...)
+}
+
+// ErrorfContext logs a formatted message at the error level using the context's logger and fields.
+func ErrorfContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).ErrorfContext(ctx, format, args...)
+}
+
+// FatalContext logs a message at the fatal level using the context's logger and fields and then panics.
+func FatalContext(ctx context.Context, a ...any) {
+	FromContext(ctx).FatalContext(ctx, a...)
+}
+
+// FatalfContext logs a formatted message at the fatal level using the context's logger and fields and then panics.
+func FatalfContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).FatalfContext(ctx, format, args...)
+}
+
+// NoticeContext logs a message at the notice level using the context's logger and fields.
+func NoticeContext(ctx context.Context, a ...any) {
+	FromContext(ctx).NoticeContext(ctx, a...)
+}
+
+// NoticefContext logs a formatted message at the notice level using the context's logger and fields.
+func NoticefContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).NoticefContext(ctx, format, args...)
+}
+
+// CriticalContext logs a message at the critical level using the context's logger and fields.
+func CriticalContext(ctx context.Context, a ...any) {
+	FromContext(ctx).CriticalContext(ctx, a...)
+}
+
+// CriticalfContext logs a formatted message at the critical level using the context's logger and fields.
+func CriticalfContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).CriticalfContext(ctx, format, args...)
+}
+
+// AlertContext logs a message at the alert level using the context's logger and fields.
+func AlertContext(ctx context.Context, a ...any) {
+	FromContext(ctx).AlertContext(ctx, a...)
+}
+
+// AlertfContext logs a formatted message at the alert level using the context's logger and fields.
+func AlertfContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).AlertfContext(ctx, format, args...)
+}
+
+// EmergencyContext logs a message at the emergency level using the context's logger and fields.
+func EmergencyContext(ctx context.Context, a ...any) {
+	FromContext(ctx).EmergencyContext(ctx, a...)
+}
+
+// EmergencyfContext logs a formatted message at the emergency level using the context's logger and fields.
+func EmergencyfContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).EmergencyfContext(ctx, format, args...)
+}
+
+// PanicContext logs a message at the panic level using the context's logger and fields and then panics.
+func PanicContext(ctx context.Context, a ...any) {
+	FromContext(ctx).PanicContext(ctx, a...)
+}
+
+// PanicfContext logs a formatted message at the panic level using the context's logger and fields and then panics.
+func PanicfContext(ctx context.Context, format string, args ...any) {
+	FromContext(ctx).PanicfContext(ctx, format, args...)
+}
@@ -80,3 +80,150 @@ func TestLogos_WithLogger(t *testing.T) {
 	// First buffer should not have new message
 	assert.Empty(t, buf1.String())
 }
+
+type testCtxKey string
+
+func resetContextExtractors(t *testing.T) {
+	contextExtractorsMu.Lock()
+	saved := contextExtractors
+	contextExtractors = nil
+	contextExtractorsMu.Unlock()
+	t.Cleanup(func() {
+		contextExtractorsMu.Lock()
+		contextExtractors = saved
+		contextExtractorsMu.Unlock()
+	})
+}
+
+func TestLogger_InfoContext_Extractors(t *testing.T) {
+	resetContextExtractors(t)
+	RegisterContextExtractor(ContextValueExtractor(testCtxKey("request_id"), "request_id"))
+	RegisterContextExtractor(func(ctx context.Context) Fields {
+		if tenant, ok := ctx.Value(testCtxKey("tenant")).(string); ok {
+			return Fields{"tenant": tenant}
+		}
+		return nil
+	})
+
+	buf := &bytes.Buffer{}
+	log := NewLogger(LevelInfo, JSONFormatter(), buf)
+
+	ctx := context.WithValue(context.Background(), testCtxKey("request_id"), "r-1")
+	log.InfoContext(ctx, "hello")
+	m := Map(buf)
+	assert.Equal(t, "r-1", m.Field("request_id"))
+	assert.Nil(t, m.Field("tenant"))
+
+	ctx = context.WithValue(ctx, testCtxKey("tenant"), "acme")
+	log.With("tenant", "explicit").WarnfContext(ctx, "%d items", 3)
+	m = Map(buf)
+	assert.Equal(t, "3 items", m["msg"])
+	assert.Equal(t, "warn", m["level"])
+	assert.Equal(t, "explicit", m.Field("tenant")) // Logger fields win
+
+	// The plain methods are unaffected.
+	log.Info("plain")
+	assert.Nil(t, Map(buf)["fields"])
+}
+
+func TestAddFields(t *testing.T) {
+	resetContextExtractors(t)
+
+	ctx := AddFields(context.Background(), Fields{"user_id": 42, "tenant": "a"})
+	ctx = AddFields(ctx, Fields{"tenant": "b"})
+	assert.Equal(t, Fields{"user_id": 42, "tenant": "b"}, ContextFields(ctx))
+	assert.Nil(t, ContextFields(context.Background()))
+
+	// Package-level functions use the default logger when the context has none.
+	buf := &bytes.Buffer{}
+	SetDefaultLogger(NewLogger(LevelInfo, JSONFormatter(), buf))
+	InfoContext(ctx, "from default")
+	m := Map(buf)
+	assert.Equal(t, "from default", m["msg"])
+	assert.EqualValues(t, 42, m.Field("user_id"))
+	assert.Equal(t, "b", m.Field("tenant"))
+
+	// And the context's logger when it has one.
+	ctxBuf := &bytes.Buffer{}
+	ctx = WithLogger(ctx, NewLogger(LevelDebug, JSONFormatter(), ctxBuf))
+	DebugfContext(ctx, "from %s", "context")
+	m = Map(ctxBuf)
+	assert.Equal(t, "from context", m["msg"])
+	assert.Equal(t, "b", m.Field("tenant"))
+}
+
+func TestLogger_LogContext_Tees(t *testing.T) {
+	resetContextExtractors(t)
+	calls := 0
+	RegisterContextExtractor(func(ctx context.Context) Fields {
+		calls++
+		return Fields{"request_id": "r-2"}
+	})
+
+	buf1 := &bytes.Buffer{}
+	buf2 := &bytes.Buffer{}
+	log := NewLogger(LevelWarn, JSONFormatter(), buf1).Tee(NewLogger(LevelDebug, TextFormatter(), buf2))
+
+	log.LogContext(context.Background(), LevelDebug-1, "nobody")
+	assert.Equal(t, 0, calls)
+
+	log.DebugContext(context.Background(), "tee only")
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, buf1.String())
+	assert.Contains(t, buf2.String(), `request_id="r-2"`)
+
+	buf2.Reset()
+	log.ErrorContext(context.Background(), "both")
+	assert.Equal(t, "r-2", Map(buf1).Field("request_id"))
+	assert.Contains(t, buf2.String(), `request_id="r-2"`)
+}
+
+func TestSeverityLadder_Context(t *testing.T) {
+	keepDefaultLogger(t)
+	buf := &bytes.Buffer{}
+	ctx := WithLogger(context.Background(), NewLogger(LevelTrace, JSONFormatter(), buf))
+
+	FromContext(ctx).TraceContext(ctx, "trace")
+	assert.Equal(t, "trace", Map(buf)["level"])
+	NoticeContext(ctx, "notice")
+	assert.Equal(t, "notice", Map(buf)["level"])
+	CriticalfContext(ctx, "%s", "critical")
+	assert.Equal(t, "critical", Map(buf)["msg"])
+	assert.Panics(t, func() { PanicContext(ctx, "panic") })
+	assert.Equal(t, "panic", Map(buf)["level"])
+
+	SetDefaultLogger(NewLogger(LevelTrace, JSONFormatter(), buf))
+	Tracef("%d", 1)
+	assert.Equal(t, "trace", Map(buf)["level"])
+	Emergency("down")
+	assert.Equal(t, "emergency", Map(buf)["level"])
+	assert.Panics(t, func() { Panicf("%s", "stop") })
+	assert.Equal(t, "stop", Map(buf)["msg"])
+}
+
+func TestPackageContext_PrintAndFatal(t *testing.T) {
+	keepDefaultLogger(t)
+	resetContextExtractors(t)
+	buf := &bytes.Buffer{}
+	SetDefaultLogger(NewLogger(LevelError, JSONFormatter(), buf))
+	ctx := AddFields(context.Background(), Fields{"request_id": "r-9"})
+
+	PrintContext(ctx, "printed")
+	entry := Map(buf)
+	assert.Equal(t, "printed", entry["msg"])
+	assert.Equal(t, "r-9", entry.Field("request_id"))
+
+	buf.Reset()
+	PrintfContext(ctx, "%d printed", 2)
+	assert.Equal(t, "2 printed", Map(buf)["msg"])
+
+	buf.Reset()
+	assert.PanicsWithValue(t, "stop", func() { FatalContext(ctx, "stop") })
+	entry = Map(buf)
+	assert.Equal(t, "fatal", entry["level"])
+	assert.Equal(t, "r-9", entry.Field("request_id"))
+
+	buf.Reset()
+	assert.PanicsWithValue(t, "stop 3", func() { FatalfContext(ctx, "stop %d", 3) })
+	assert.Equal(t, "stop 3", Map(buf)["msg"])
+}
@@ -1,8 +1,8 @@
 package logos
 
 import (
+	"fmt"
 	"os"
-	"strings"
 	"sync"
 )
 
@@ -10,26 +10,14 @@ import (
 var defaultLogger Logger
 var defaultLoggerMu sync.RWMutex
 
-// init sets the default logger to output debug-level logs to the console.
+// init configures the default logger from the LOG_ environment variables; see
+// ConfigureFromEnv. Without them, it logs debug-level entries to the console, colored only
+// when stdout is a terminal (see ColorEnabled). Invalid variables are reported on stderr.
 func init() {
-	level := LevelDebug
-	if logLevel := strings.ToLower(os.Getenv("LOG_LEVEL")); logLevel != "" {
-		if lvl, ok := DefaultLevels[logLevel]; ok {
-			level = lvl
-		}
+	if err := ConfigureFromEnv(DefaultEnvPrefix); err != nil {
+		fmt.Fprintf(os.Stderr, "logos: %v\n", err)
+		defaultLogger = NewLogger(LevelDebug, AutoConsoleFormatter(os.Stdout), os.Stdout)
 	}
-
-	formatter := ConsoleFormatter()
-	switch strings.ToLower(os.Getenv("LOG_FORMAT")) {
-	case "json":
-		formatter = JSONFormatter()
-	case "text":
-		formatter = TextFormatter()
-	case "console":
-		formatter = ConsoleFormatter()
-	}
-
-	defaultLogger = NewLogger(level, formatter, os.Stdout)
 }
 
 // SetDefaultLogger overrides the global default logger with a new one.
@@ -159,3 +147,63 @@ func Fatal(a ...any) {
 func Fatalf(format string, args ...any) {
 	getDefaultLogger().Fatalf(format, args...)
 }
+
+// Trace logs a message at the trace level using the default logger.
+func Trace(a ...any) {
+	getDefaultLogger().Trace(a...)
+}
+
+// Tracef logs a formatted message at the trace level using the default logger.
+func Tracef(format string, args ...any) {
+	getDefaultLogger().Tracef(format, args...)
+}
+
+// Notice logs a message at the notice level using the default logger.
+func Notice(a ...any) {
+	getDefaultLogger().Notice(a...)
+}
+
+// Noticef logs a formatted message at the notice level using the default logger.
+func Noticef(format string, args ...any) {
+	getDefaultLogger().Noticef(format, args...)
+}
+
+// Critical logs a message at the critical level using the default logger.
+func Critical(a ...any) {
+	getDefaultLogger().Critical(a...)
+}
+
+// Criticalf logs a formatted message at the critical level using the default logger.
+func Criticalf(format string, args ...any) {
+	getDefaultLogger().Criticalf(format, args...)
+}
+
+// Alert logs a message at the alert level using the default logger.
+func Alert(a ...any) {
+	getDefaultLogger().Alert(a...)
+}
+
+// Alertf logs a formatted message at the alert level using the default logger.
+func Alertf(format string, args ...any) {
+	getDefaultLogger().Alertf(format, args...)
+}
+
+// Emergency logs a message at the emergency level using the default logger.
+func Emergency(a ...any) {
+	getDefaultLogger().Emergency(a...)
+}
+
+// Emergencyf logs a formatted message at the emergency level using the default logger.
+func Emergencyf(format string, args ...any) {
+	getDefaultLogger().Emergencyf(format, args...)
+}
+
+// Panic logs a message at the panic level using the default logger and panics.
+func Panic(a ...any) {
+	getDefaultLogger().Panic(a...)
+}
+
+// Panicf logs a formatted message at the panic level using the default logger and panics.
+func Panicf(format string, args ...any) {
+	getDefaultLogger().Panicf(format, args...)
+}
@@ -6,54 +6,55 @@ import (
 	"github.com/goodblaster/logos"
 )
 
-// This demo shows how to create custom log levels.
+// This demo shows the built-in severity ladder and how to add a custom level.
 func main() {
-	// Define custom levels
-	const (
-		LevelTrace   logos.Level = iota - 2 // Lower number = more verbose
-		LevelVerbose
-		LevelNotice  logos.Level = iota + 2 // Between Warn and Error
-	)
-
-	// Register custom level names using thread-safe setter
-	logos.SetLevelName(LevelTrace, "trace")
-	logos.SetLevelName(LevelVerbose, "verbose")
-	logos.SetLevelName(LevelNotice, "notice")
-
-	// Register custom colors (optional, for console formatter)
-	logos.SetLevelColor(LevelTrace, logos.ColorTextCyan)
-	logos.SetLevelColor(LevelVerbose, logos.ColorTextBlue)
-	logos.SetLevelColor(LevelNotice, logos.ColorTextMagenta)
-
-	// Cleanup after demo
-	defer func() {
-		logos.SetLevelName(LevelTrace, "")
-		logos.SetLevelName(LevelVerbose, "")
-		logos.SetLevelName(LevelNotice, "")
-	}()
-
-	log := logos.NewLogger(LevelTrace, logos.ConsoleFormatter(), os.Stdout)
-
-	logos.Print("Custom log levels:")
-	logos.Print("===================\n")
-
-	// Use custom levels
-	log.Log(LevelTrace, "This is a trace message (most verbose)")
-	log.Log(LevelVerbose, "This is a verbose message")
-	log.Log(logos.LevelDebug, "This is a debug message")
-	log.Log(logos.LevelInfo, "This is an info message")
-	log.Log(logos.LevelWarn, "This is a warning message")
-	log.Log(LevelNotice, "This is a notice message (between warn and error)")
-	log.Log(logos.LevelError, "This is an error message")
+	// Trace, Notice, Critical, Alert and Emergency are built in; only truly
+	// application-specific levels need registering. Values above LevelPanic are
+	// free, and Rank makes the level filter right after notice.
+	const LevelAudit = logos.LevelPanic + 1
+
+	err := logos.RegisterLevel(LevelAudit, logos.LevelSpec{
+		Name:           "audit",
+		Color:          logos.ColorTextMagenta,
+		SyslogSeverity: logos.Syslog(logos.SyslogNotice),
+		OTelSeverity:   logos.OTelSeverityInfo + 2,
+		Rank:           logos.After(logos.LevelNotice),
+	})
+	if err != nil {
+		panic(err)
+	}
+	defer logos.UnregisterLevel(LevelAudit)
+
+	log := logos.NewLogger(logos.LevelTrace, logos.ConsoleFormatter(), os.Stdout)
+
+	logos.Print("Built-in levels:")
+	logos.Print("================\n")
+
+	log.Trace("This is a trace message (most verbose)")
+	log.Debug("This is a debug message")
+	log.Info("This is an info message")
+	log.Notice("This is a notice message")
+	log.Warn("This is a warning message")
+	log.Error("This is an error message")
+	log.Critical("This is a critical message")
+	log.Alert("This is an alert message")
+	log.Emergency("This is an emergency message")
+	log.Log(LevelAudit, "This is a custom audit message")
 
 	// Change level to filter out verbose messages
-	logos.Print("\n\nWith level set to Debug (Trace and Verbose filtered):")
-	logos.Print("========================================================")
+	logos.Print("\n\nWith level set to Debug (Trace filtered):")
+	logos.Print("=========================================")
 	log = log.WithLevel(logos.LevelDebug)
 
-	log.Log(LevelTrace, "This won't show")
-	log.Log(LevelVerbose, "This won't show")
-	log.Log(logos.LevelDebug, "This is a debug message")
-	log.Log(logos.LevelInfo, "This is an info message")
-	log.Log(LevelNotice, "This is a notice message")
+	log.Trace("This won't show")
+	log.Debug("This is a debug message")
+	log.Notice("This is a notice message")
+
+	// Audit ranks below warn, so a logger at warn drops it
+	logos.Print("\n\nWith level set to Warn (Audit filtered):")
+	logos.Print("========================================")
+	log = log.WithLevel(logos.LevelWarn)
+
+	log.Log(LevelAudit, "This won't show")
+	log.Warn("This is a warning message")
 }
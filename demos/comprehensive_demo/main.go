@@ -59,17 +59,9 @@ func main() {
 		LevelCherry
 	)
 
-	logos.LevelNames = map[logos.Level]string{
-		LevelApple:  "apple",
-		LevelBanana: "banana",
-		LevelCherry: "cherry",
-	}
-
-	logos.LevelColors = map[logos.Level]logos.Color{
-		LevelApple:  logos.ColorBgGreen + logos.ColorTextBlack,
-		LevelBanana: logos.ColorBgYellow + logos.ColorTextBlack,
-		LevelCherry: logos.ColorBgRed + logos.ColorTextBlack,
-	}
+	_ = logos.RegisterLevel(LevelApple, logos.LevelSpec{Name: "apple", Color: logos.ColorBgGreen + logos.ColorTextBlack})
+	_ = logos.RegisterLevel(LevelBanana, logos.LevelSpec{Name: "banana", Color: logos.ColorBgYellow + logos.ColorTextBlack})
+	_ = logos.RegisterLevel(LevelCherry, logos.LevelSpec{Name: "cherry", Color: logos.ColorBgRed + logos.ColorTextBlack})
 
 	logos.Print("")
 	logos.Print("CUSTOM LEVELS ----------")
@@ -172,7 +164,7 @@ func (f customFormatter) Format(level logos.Level, entry logos.Entry) string {
 	}
 	slices.Sort(tuples)
 
-	textColor := logos.LevelColors[level]
+	textColor := logos.GetLevelColor(level, nil)
 
 	line := fmt.Sprintf("%s\t%s",
 		strings.ToUpper(level.String()),
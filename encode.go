@@ -0,0 +1,173 @@
+package logos
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Default limits applied when encoding field values. They can be changed per formatter
+// with Config.MaxFieldDepth and Config.MaxFieldSize.
+const (
+	DefaultMaxFieldDepth = 32
+	DefaultMaxFieldSize  = 64 << 10
+)
+
+// FieldError replaces a field value that could not be encoded, such as a channel, a function,
+// a NaN float, a cyclic structure, or a value exceeding the configured depth or size limits.
+// The rest of the entry is still written.
+type FieldError struct {
+	Error string `json:"marshal_error"`
+	Type  string `json:"type"`
+}
+
+// String renders the FieldError as used by the text and console formatters.
+func (e FieldError) String() string {
+	return fmt.Sprintf("<marshal_error type=%s: %s>", e.Type, e.Error)
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// encodeField marshals a single field value to JSON, enforcing the depth and size limits
+// from cfg. LogValuer and LogMarshaler values are resolved first. On failure, it returns
+// a FieldError describing the offending value instead.
+func encodeField(value any, cfg *Config) ([]byte, *FieldError) {
+	value, _ = resolveLogValue(value, 0)
+	if fieldErr, ok := value.(*FieldError); ok {
+		return nil, fieldErr
+	}
+
+	maxDepth, maxSize := DefaultMaxFieldDepth, DefaultMaxFieldSize
+	if cfg != nil && cfg.MaxFieldDepth > 0 {
+		maxDepth = cfg.MaxFieldDepth
+	}
+	if cfg != nil && cfg.MaxFieldSize > 0 {
+		maxSize = cfg.MaxFieldSize
+	}
+
+	if err := checkValue(reflect.ValueOf(value), 0, maxDepth, map[uintptr]bool{}); err != nil {
+		return nil, newFieldError(value, err)
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, newFieldError(value, err)
+	}
+	if len(b) > maxSize {
+		return nil, newFieldError(value, fmt.Errorf("encoded size %d exceeds limit of %d bytes", len(b), maxSize))
+	}
+	return b, nil
+}
+
+// encodeFieldString returns the JSON encoding of a field value for the text-based formatters,
+// or the FieldError marker when the value cannot be encoded.
+func encodeFieldString(value any, cfg *Config) string {
+	b, fieldErr := encodeField(value, cfg)
+	if fieldErr != nil {
+		return fieldErr.String()
+	}
+	return string(b)
+}
+
+// newFieldError describes a value that could not be encoded.
+func newFieldError(value any, err error) *FieldError {
+	return &FieldError{Error: err.Error(), Type: fmt.Sprintf("%T", value)}
+}
+
+// checkValue walks a value the way encoding/json would, reporting reference cycles and
+// nesting deeper than maxDepth before json.Marshal recurses into them.
+func checkValue(v reflect.Value, depth, maxDepth int, path map[uintptr]bool) error {
+	if !v.IsValid() {
+		return nil
+	}
+
+	// Values with their own encoding are not walked.
+	if v.Kind() != reflect.Interface && (v.Type().Implements(jsonMarshalerType) || v.Type().Implements(textMarshalerType)) {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return checkValue(v.Elem(), depth, maxDepth, path)
+
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		ptr := v.Pointer()
+		if path[ptr] {
+			return fmt.Errorf("cycle detected via %s", v.Type())
+		}
+		path[ptr] = true
+		defer delete(path, ptr)
+		return checkValue(v.Elem(), depth, maxDepth, path)
+
+	case reflect.Struct:
+		if depth >= maxDepth {
+			return fmt.Errorf("nesting exceeds max depth of %d", maxDepth)
+		}
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if field := t.Field(i); field.PkgPath != "" && !field.Anonymous {
+				continue
+			}
+			if err := checkValue(v.Field(i), depth+1, maxDepth, path); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		if depth >= maxDepth {
+			return fmt.Errorf("nesting exceeds max depth of %d", maxDepth)
+		}
+		ptr := v.Pointer()
+		if path[ptr] {
+			return fmt.Errorf("cycle detected via %s", v.Type())
+		}
+		path[ptr] = true
+		defer delete(path, ptr)
+		iter := v.MapRange()
+		for iter.Next() {
+			if err := checkValue(iter.Value(), depth+1, maxDepth, path); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if depth >= maxDepth {
+			return fmt.Errorf("nesting exceeds max depth of %d", maxDepth)
+		}
+		if !mayNest(v.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			if err := checkValue(v.Index(i), depth+1, maxDepth, path); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// mayNest reports whether values of type t can contain further structure worth walking.
+func mayNest(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Struct, reflect.Map, reflect.Slice, reflect.Array:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package logos
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeField(t *testing.T) {
+	type inner struct {
+		Name string
+	}
+	type outer struct {
+		Inner  inner
+		hidden chan int // Unexported fields are ignored, as with encoding/json
+	}
+
+	b, fieldErr := encodeField(outer{Inner: inner{Name: "x"}}, nil)
+	assert.Nil(t, fieldErr)
+	assert.Equal(t, `{"Inner":{"Name":"x"}}`, string(b))
+
+	// Values with their own encoding are not walked.
+	b, fieldErr = encodeField(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), nil)
+	assert.Nil(t, fieldErr)
+	assert.Equal(t, `"2024-01-02T03:04:05Z"`, string(b))
+
+	// Shared (non-cyclic) pointers are fine.
+	shared := &inner{Name: "shared"}
+	_, fieldErr = encodeField([]*inner{shared, shared}, nil)
+	assert.Nil(t, fieldErr)
+
+	_, fieldErr = encodeField(math.Inf(1), nil)
+	if assert.NotNil(t, fieldErr) {
+		assert.Equal(t, "float64", fieldErr.Type)
+	}
+}
+
+func TestEncodeField_Cycles(t *testing.T) {
+	m := map[string]any{}
+	m["self"] = m
+	_, fieldErr := encodeField(m, nil)
+	if assert.NotNil(t, fieldErr) {
+		assert.Contains(t, fieldErr.Error, "cycle detected")
+		assert.Equal(t, "map[string]interface {}", fieldErr.Type)
+	}
+}
+
+func TestEncodeFieldString(t *testing.T) {
+	assert.Equal(t, `"value"`, encodeFieldString("value", nil))
+	assert.Equal(t, "<marshal_error type=chan int: json: unsupported type: chan int>", encodeFieldString(make(chan int), nil))
+}
@@ -0,0 +1,196 @@
+package logos
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goodblaster/errors"
+)
+
+// DefaultEnvPrefix is the prefix of the environment variables read at package initialization.
+const DefaultEnvPrefix = "LOG_"
+
+// envCloser closes the file opened by the last ConfigureFromEnv or flag. Guarded by defaultLoggerMu.
+var envCloser io.Closer
+
+// ConfigureFromEnv replaces the default logger with one configured by environment variables
+// named with the prefix, which defaults to DefaultEnvPrefix:
+//
+//	LEVEL        default level (debug, info, ...). Defaults to debug.
+//	LEVELS       levels of named loggers, e.g. "db=debug,http.client=warn"
+//	FORMAT       a FormatNames entry, case-insensitive. Defaults to console.
+//	OUTPUT       "stdout", "stderr" or a file path. Defaults to stdout.
+//	CALLER       record call sites, as accepted by strconv.ParseBool
+//	FIELDS       static fields, e.g. "service=api,env=prod"
+//	SAMPLING     sampler settings, e.g. "first=10,thereafter=100,tick=1s"
+//	TIME_FORMAT  Go time layout, "rfc3339" or "rfc3339nano"
+//	TIMEZONE     IANA time zone name, "UTC" or "Local"
+//	THEME        console theme, also set on DefaultConfig for other formatters
+//
+// With the prefix "MYAPP_LOG_", the level is read from MYAPP_LOG_LEVEL. An invalid value is
+// returned as an error naming the variable, and the default logger is left unchanged.
+// It is called with DefaultEnvPrefix when the package is initialized.
+func ConfigureFromEnv(prefix string) error {
+	if prefix == "" {
+		prefix = DefaultEnvPrefix
+	}
+	cfg, err := envConfig(prefix)
+	if err != nil {
+		return err
+	}
+	if err := setDefaultConfig(cfg); err != nil {
+		return errors.Wrap(err, "%sOUTPUT", prefix)
+	}
+	return nil
+}
+
+// setDefaultConfig builds cfg and makes it the default logger, closing the file opened by
+// the previous call, if any.
+func setDefaultConfig(cfg LoggerConfig) error {
+	logger, closer, err := cfg.Build()
+	if err != nil {
+		return err
+	}
+
+	defaultLoggerMu.Lock()
+	defer defaultLoggerMu.Unlock()
+	if cfg.Theme != "" {
+		theme, _ := LookupTheme(cfg.Theme)
+		DefaultConfig.Theme = &theme
+	}
+	previous := envCloser
+	defaultLogger, envCloser = logger, closer
+	if previous != nil {
+		_ = previous.Close()
+	}
+	return nil
+}
+
+// envConfig reads and validates the variables read by ConfigureFromEnv.
+func envConfig(prefix string) (LoggerConfig, error) {
+	cfg := LoggerConfig{Level: "debug"}
+	output := OutputConfig{}
+	invalid := func(name, value string) error {
+		return errors.New("invalid %s%s %q", prefix, name, value)
+	}
+
+	if value := os.Getenv(prefix + "LEVEL"); value != "" {
+		if _, err := ParseLevel(value); err != nil {
+			return cfg, invalid("LEVEL", value)
+		}
+		cfg.Level = value
+	}
+	if value := os.Getenv(prefix + "LEVELS"); value != "" {
+		pairs, ok := parseEnvPairs(value)
+		if !ok {
+			return cfg, invalid("LEVELS", value)
+		}
+		for _, level := range pairs {
+			if _, err := ParseLevel(level); err != nil {
+				return cfg, invalid("LEVELS", value)
+			}
+		}
+		cfg.Levels = pairs
+	}
+	if value := os.Getenv(prefix + "FORMAT"); value != "" {
+		if _, err := ParseFormat(value); err != nil {
+			return cfg, invalid("FORMAT", value)
+		}
+		output.Format = value
+	}
+	switch value := os.Getenv(prefix + "OUTPUT"); strings.ToLower(value) {
+	case "", "stdout", "stderr":
+		output.Destination = strings.ToLower(value)
+	default:
+		output.File = &FileConfig{Path: value}
+	}
+	if value := os.Getenv(prefix + "CALLER"); value != "" {
+		caller, err := strconv.ParseBool(value)
+		if err != nil {
+			return cfg, invalid("CALLER", value)
+		}
+		cfg.Caller = caller
+	}
+	if value := os.Getenv(prefix + "FIELDS"); value != "" {
+		pairs, ok := parseEnvPairs(value)
+		if !ok {
+			return cfg, invalid("FIELDS", value)
+		}
+		cfg.Fields = make(Fields, len(pairs))
+		for key, field := range pairs {
+			cfg.Fields[key] = field
+		}
+	}
+	if value := os.Getenv(prefix + "SAMPLING"); value != "" {
+		sampling, err := parseEnvSampling(value)
+		if err != nil {
+			return cfg, errors.Wrap(err, "invalid %sSAMPLING %q", prefix, value)
+		}
+		cfg.Sampling = &sampling
+	}
+	if value := os.Getenv(prefix + "THEME"); value != "" {
+		if _, ok := LookupTheme(value); !ok {
+			return cfg, invalid("THEME", value)
+		}
+		cfg.Theme = value
+	}
+	cfg.TimeFormat = os.Getenv(prefix + "TIME_FORMAT")
+	if value := os.Getenv(prefix + "TIMEZONE"); value != "" {
+		if _, err := time.LoadLocation(value); err != nil {
+			return cfg, invalid("TIMEZONE", value)
+		}
+		cfg.TimeZone = value
+	}
+
+	cfg.Outputs = []OutputConfig{output}
+	return cfg, nil
+}
+
+// parseEnvPairs parses a comma-separated list of key=value pairs.
+func parseEnvPairs(s string) (map[string]string, bool) {
+	pairs := map[string]string{}
+	for _, item := range strings.Split(s, ",") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(item, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, false
+		}
+		pairs[key] = strings.TrimSpace(value)
+	}
+	return pairs, true
+}
+
+// parseEnvSampling parses the pairs of a SAMPLING variable and validates them.
+func parseEnvSampling(s string) (SamplingConfig, error) {
+	var sampling SamplingConfig
+	pairs, ok := parseEnvPairs(s)
+	if !ok {
+		return sampling, errors.New("expected key=value pairs")
+	}
+	for key, value := range pairs {
+		var err error
+		switch strings.ToLower(key) {
+		case "first":
+			sampling.First, err = strconv.Atoi(value)
+		case "thereafter":
+			sampling.Thereafter, err = strconv.Atoi(value)
+		case "tick":
+			sampling.Tick = value
+		default:
+			return sampling, errors.New("unknown key %q", key)
+		}
+		if err != nil {
+			return sampling, errors.Wrap(err, "invalid %s", key)
+		}
+	}
+	if _, err := sampling.sampler(); err != nil {
+		return sampling, err
+	}
+	return sampling, nil
+}
@@ -0,0 +1,70 @@
+package logos
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// keepDefaultLogger restores the default logger when the test ends.
+func keepDefaultLogger(t *testing.T) {
+	previous := getDefaultLogger()
+	t.Cleanup(func() { SetDefaultLogger(previous) })
+}
+
+func TestConfigureFromEnv(t *testing.T) {
+	keepDefaultLogger(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv("MYAPP_LOG_LEVEL", "warn")
+	t.Setenv("MYAPP_LOG_LEVELS", "db=debug, http.client=error")
+	t.Setenv("MYAPP_LOG_FORMAT", "JSON")
+	t.Setenv("MYAPP_LOG_OUTPUT", path)
+	t.Setenv("MYAPP_LOG_FIELDS", "service=api,env=prod")
+	t.Setenv("MYAPP_LOG_SAMPLING", "first=1,tick=1h")
+	t.Setenv("MYAPP_LOG_TIME_FORMAT", "2006")
+	t.Setenv("MYAPP_LOG_TIMEZONE", "UTC")
+
+	assert.NoError(t, ConfigureFromEnv("MYAPP_LOG_"))
+	assert.Equal(t, LevelWarn, getDefaultLogger().GetLevel())
+	Info("dropped")
+	db := getDefaultLogger().Named("db")
+	db.Debug("query")
+	db.Debug("query") // Sampled out
+	getDefaultLogger().Named("http.client").Warn("dropped")
+	assert.NoError(t, envCloser.Close())
+
+	lines := readLines(t, path)
+	if assert.Len(t, lines, 1) {
+		assert.Equal(t, "query", lines[0]["msg"])
+		assert.Equal(t, "debug", lines[0]["level"])
+		assert.Len(t, lines[0]["timestamp"], 4)
+		assert.Equal(t, map[string]any{"service": "api", "env": "prod"}, lines[0]["fields"])
+	}
+}
+
+func TestConfigureFromEnv_Invalid(t *testing.T) {
+	keepDefaultLogger(t)
+	before := getDefaultLogger()
+
+	tests := map[string]string{
+		"LEVEL":    "loud",
+		"LEVELS":   "db",
+		"FORMAT":   "xml",
+		"CALLER":   "sometimes",
+		"FIELDS":   "=prod",
+		"SAMPLING": "first=ten",
+		"THEME":    "neon",
+		"TIMEZONE": "Mars/Olympus",
+	}
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("TEST_LOG_"+name, value)
+			err := ConfigureFromEnv("TEST_LOG_")
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), "invalid TEST_LOG_"+name)
+			}
+			assert.Equal(t, before, getDefaultLogger())
+		})
+	}
+}
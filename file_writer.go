@@ -0,0 +1,246 @@
+package logos
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goodblaster/errors"
+)
+
+// backupTimeFormat is the timestamp inserted into the names of rotated files.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// FileOptions configures a FileWriter.
+type FileOptions struct {
+	Path       string        // File to write. Its directory is created if needed.
+	MaxSize    int64         // Rotate before the file would exceed this many bytes. Zero disables rotation.
+	MaxBackups int           // Number of rotated files to keep. Zero keeps them all.
+	MaxAge     time.Duration // Remove rotated files older than this. Zero keeps them regardless of age.
+	Compress   bool          // Gzip rotated files.
+	Mode       os.FileMode   // Permissions of new files. Defaults to 0644.
+}
+
+// FileWriter is an io.Writer appending to a file and rotating it by size. Rotated files are
+// renamed with a timestamp, as in "app-2024-01-02T15-04-05.000.log", and pruned according to
+// MaxBackups and MaxAge. A FileWriter is safe for concurrent use.
+type FileWriter struct {
+	opts   FileOptions
+	mu     sync.Mutex
+	file   *os.File // Nil after Close or a failed reopen, which the next write retries
+	closed bool
+	size   int64
+	now    func() time.Time
+}
+
+// OpenFile opens, or creates, the file described by opts for appending.
+func OpenFile(opts FileOptions) (*FileWriter, error) {
+	if opts.Path == "" {
+		return nil, errors.New("file path is required")
+	}
+	if opts.Mode == 0 {
+		opts.Mode = 0o644
+	}
+
+	w := &FileWriter{opts: opts, now: time.Now}
+	if err := w.open(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write appends p to the file, rotating it first if p would take it past MaxSize.
+// An entry larger than MaxSize is written to a fresh file on its own. If rotation fails, p is
+// still appended to the current file and the rotation error is returned.
+func (w *FileWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.ready(); err != nil {
+		return 0, err
+	}
+	var rotateErr error
+	if w.opts.MaxSize > 0 && w.size > 0 && w.size+int64(len(p)) > w.opts.MaxSize {
+		rotateErr = w.rotate()
+		if w.file == nil {
+			return 0, rotateErr
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	if err == nil {
+		err = rotateErr
+	}
+	return n, err
+}
+
+// Rotate closes the current file, renames it as a backup and starts a new one. If the
+// rename fails, writing continues to the current file.
+func (w *FileWriter) Rotate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.ready(); err != nil {
+		return err
+	}
+	return w.rotate()
+}
+
+// Close closes the file. Later writes fail.
+func (w *FileWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+// ready reopens the file if a rotation left it closed. It must be called with the lock held.
+func (w *FileWriter) ready() error {
+	if w.closed {
+		return errors.New("file %s is closed", w.opts.Path)
+	}
+	if w.file == nil {
+		return w.open()
+	}
+	return nil
+}
+
+// open opens the file for appending and records its current size.
+func (w *FileWriter) open() error {
+	if err := os.MkdirAll(filepath.Dir(w.opts.Path), 0o755); err != nil {
+		return errors.Wrap(err, "failed to create log directory")
+	}
+	file, err := os.OpenFile(w.opts.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, w.opts.Mode)
+	if err != nil {
+		return errors.Wrap(err, "failed to open log file")
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return errors.Wrap(err, "failed to stat log file")
+	}
+	w.file = file
+	w.size = info.Size()
+	return nil
+}
+
+// rotate must be called with the lock held. If the file cannot be renamed, or the new one
+// cannot be created, it reopens the original file; if even that fails, the file stays
+// closed until the next write reopens it.
+func (w *FileWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return errors.Wrap(err, "failed to close log file")
+	}
+	w.file = nil
+
+	backup := w.backupName(w.now())
+	if err := os.Rename(w.opts.Path, backup); err != nil {
+		_ = w.open()
+		return errors.Wrap(err, "failed to rename log file")
+	}
+	if err := w.open(); err != nil {
+		if os.Rename(backup, w.opts.Path) == nil {
+			_ = w.open()
+		}
+		return err
+	}
+
+	if w.opts.Compress {
+		if err := compressFile(backup); err != nil {
+			return err
+		}
+	}
+	return w.prune()
+}
+
+// backupName returns the name of a backup made at t.
+func (w *FileWriter) backupName(t time.Time) string {
+	dir, prefix, ext := w.nameParts()
+	return filepath.Join(dir, prefix+t.Format(backupTimeFormat)+ext)
+}
+
+// nameParts splits the path into the directory, the backup name prefix and the extension.
+func (w *FileWriter) nameParts() (dir, prefix, ext string) {
+	dir = filepath.Dir(w.opts.Path)
+	base := filepath.Base(w.opts.Path)
+	ext = filepath.Ext(base)
+	return dir, strings.TrimSuffix(base, ext) + "-", ext
+}
+
+// prune removes the backups exceeding MaxBackups or older than MaxAge.
+func (w *FileWriter) prune() error {
+	if w.opts.MaxBackups <= 0 && w.opts.MaxAge <= 0 {
+		return nil
+	}
+
+	type backup struct {
+		path string
+		time time.Time
+	}
+	dir, prefix, ext := w.nameParts()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to list log backups")
+	}
+	var backups []backup
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".gz")
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		t, err := time.Parse(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext))
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backup{path: filepath.Join(dir, entry.Name()), time: t})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].time.After(backups[j].time) })
+
+	cutoff := w.now().Add(-w.opts.MaxAge)
+	for i, b := range backups {
+		if (w.opts.MaxBackups > 0 && i >= w.opts.MaxBackups) || (w.opts.MaxAge > 0 && b.time.Before(cutoff)) {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				return errors.Wrap(err, "failed to remove log backup")
+			}
+		}
+	}
+	return nil
+}
+
+// compressFile replaces path with a gzipped copy named path + ".gz".
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return errors.Wrap(err, "failed to open log backup")
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "failed to create compressed log backup")
+	}
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		return errors.Wrap(err, "failed to compress log backup")
+	}
+	if err := gz.Close(); err != nil {
+		_ = dst.Close()
+		return errors.Wrap(err, "failed to compress log backup")
+	}
+	if err := dst.Close(); err != nil {
+		return errors.Wrap(err, "failed to compress log backup")
+	}
+	_ = src.Close()
+	return os.Remove(path)
+}
@@ -0,0 +1,125 @@
+package logos
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func backups(t *testing.T, dir string) []string {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		if entry.Name() != "app.log" {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestFileWriter_Rotate(t *testing.T) {
+	dir := t.TempDir()
+	w, err := OpenFile(FileOptions{Path: filepath.Join(dir, "logs", "app.log"), MaxSize: 10, MaxBackups: 2})
+	assert.NoError(t, err)
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	for _, line := range []string{"aaaa\n", "bbbb\n", "cccc\n", "dddd\n", "eeee\n", "ffff\n", "gggg\n"} {
+		now = now.Add(time.Second)
+		_, err := w.Write([]byte(line))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, w.Close())
+	_, err = w.Write([]byte("closed\n"))
+	assert.Error(t, err)
+
+	logs := filepath.Join(dir, "logs")
+	current, _ := os.ReadFile(filepath.Join(logs, "app.log"))
+	assert.Equal(t, "gggg\n", string(current))
+	assert.Equal(t, []string{"app-2024-01-02T15-04-10.000.log", "app-2024-01-02T15-04-12.000.log"}, backups(t, logs))
+	newest, _ := os.ReadFile(filepath.Join(logs, "app-2024-01-02T15-04-12.000.log"))
+	assert.Equal(t, "eeee\nffff\n", string(newest))
+}
+
+func TestFileWriter_RotateFailure(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	w, err := OpenFile(FileOptions{Path: path, MaxSize: 10})
+	assert.NoError(t, err)
+	now := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	// A non-empty directory where the backup should go makes the rename fail
+	blocked := w.backupName(now)
+	assert.NoError(t, os.MkdirAll(filepath.Join(blocked, "sub"), 0o755))
+
+	_, err = w.Write([]byte("aaaa\n"))
+	assert.NoError(t, err)
+	assert.Error(t, w.Rotate())
+	n, err := w.Write([]byte("bbbbbbbb\n")) // Rotation fails again, but the entry is kept
+	assert.ErrorContains(t, err, "failed to rename log file")
+	assert.Equal(t, 9, n)
+
+	now = now.Add(time.Second)
+	_, err = w.Write([]byte("cccc\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+
+	current, _ := os.ReadFile(path)
+	assert.Equal(t, "cccc\n", string(current))
+	backup, _ := os.ReadFile(w.backupName(now))
+	assert.Equal(t, "aaaa\nbbbbbbbb\n", string(backup))
+}
+
+func TestFileWriter_CompressAndMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	w, err := OpenFile(FileOptions{Path: filepath.Join(dir, "app.log"), Compress: true, MaxAge: time.Hour})
+	assert.NoError(t, err)
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	w.now = func() time.Time { return now }
+
+	_, _ = w.Write([]byte("old\n"))
+	assert.NoError(t, w.Rotate())
+	now = now.Add(2 * time.Hour)
+	_, _ = w.Write([]byte("new\n"))
+	assert.NoError(t, w.Rotate())
+	assert.NoError(t, w.Close())
+
+	assert.Equal(t, []string{"app-2024-01-02T17-00-00.000.log.gz"}, backups(t, dir))
+	f, err := os.Open(filepath.Join(dir, "app-2024-01-02T17-00-00.000.log.gz"))
+	assert.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	assert.NoError(t, err)
+	b, _ := io.ReadAll(gz)
+	assert.Equal(t, "new\n", string(b))
+}
+
+func TestFileWriter_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	assert.NoError(t, os.WriteFile(path, []byte("existing\n"), 0o644))
+
+	w, err := OpenFile(FileOptions{Path: path})
+	assert.NoError(t, err)
+	NewLogger(LevelInfo, mustPatternFormatter(t, "%{msg}"), w).Info("appended")
+	assert.NoError(t, w.Close())
+
+	b, _ := os.ReadFile(path)
+	assert.Equal(t, "existing\nappended\n", string(b))
+}
+
+func mustPatternFormatter(t *testing.T, layout string) Formatter {
+	t.Helper()
+	f, err := NewPatternFormatter(layout, DefaultConfig)
+	assert.NoError(t, err)
+	return f
+}
@@ -0,0 +1,140 @@
+package logos
+
+import (
+	"flag"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// RegisterFlags adds flags configuring the default logger to fs:
+//
+//	-log-level   default level, as accepted by ParseLevel
+//	-log-format  a FormatNames entry, case-insensitive
+//	-log-output  "stdout", "stderr" or a file path
+//	-log-caller  record call sites
+//
+// The default logger is rebuilt as each flag is set, starting from the LOG_ environment
+// variables (see ConfigureFromEnv), so flags take precedence over them.
+func RegisterFlags(fs *flag.FlagSet) {
+	cfg, err := envConfig(DefaultEnvPrefix)
+	if err != nil {
+		cfg = LoggerConfig{Level: "debug", Outputs: []OutputConfig{{}}}
+	}
+	flags := &defaultFlags{cfg: cfg}
+
+	fs.Var(&configFlag{flags: flags, get: flagLevel, set: setFlagLevel},
+		"log-level", "minimum `level` of the default logger")
+	fs.Var(&configFlag{flags: flags, get: flagFormat, set: setFlagFormat},
+		"log-format", "`format` of the default logger: json, text, console, gcp, otel or ecs")
+	fs.Var(&configFlag{flags: flags, get: flagOutput, set: setFlagOutput},
+		"log-output", "`destination` of the default logger: stdout, stderr or a file path")
+	fs.Var(&configFlag{flags: flags, get: flagCaller, set: setFlagCaller, isBool: true},
+		"log-caller", "record the call site of each entry of the default logger")
+}
+
+// defaultFlags holds the configuration built into the default logger by RegisterFlags.
+type defaultFlags struct {
+	mu  sync.Mutex
+	cfg LoggerConfig
+}
+
+// configFlag is a flag.Value reading and updating one setting of the configuration.
+type configFlag struct {
+	flags  *defaultFlags
+	get    func(cfg LoggerConfig) string
+	set    func(cfg *LoggerConfig, value string) error
+	isBool bool
+}
+
+func (c *configFlag) String() string {
+	if c == nil || c.flags == nil {
+		return ""
+	}
+	c.flags.mu.Lock()
+	defer c.flags.mu.Unlock()
+	return c.get(c.flags.cfg)
+}
+
+// Set applies the value and rebuilds the default logger. The configuration is left unchanged
+// if the value is invalid or the logger cannot be built.
+func (c *configFlag) Set(value string) error {
+	c.flags.mu.Lock()
+	defer c.flags.mu.Unlock()
+
+	cfg := c.flags.cfg
+	cfg.Outputs = []OutputConfig{cfg.Outputs[0]}
+	if err := c.set(&cfg, value); err != nil {
+		return err
+	}
+	if err := setDefaultConfig(cfg); err != nil {
+		return err
+	}
+	c.flags.cfg = cfg
+	return nil
+}
+
+func (c *configFlag) IsBoolFlag() bool {
+	return c.isBool
+}
+
+func flagLevel(cfg LoggerConfig) string {
+	return cfg.Level
+}
+
+func setFlagLevel(cfg *LoggerConfig, value string) error {
+	if _, err := ParseLevel(value); err != nil {
+		return err
+	}
+	cfg.Level = value
+	return nil
+}
+
+func flagFormat(cfg LoggerConfig) string {
+	if cfg.Outputs[0].Format == "" {
+		return "console"
+	}
+	return strings.ToLower(cfg.Outputs[0].Format)
+}
+
+func setFlagFormat(cfg *LoggerConfig, value string) error {
+	if _, err := ParseFormat(value); err != nil {
+		return err
+	}
+	cfg.Outputs[0].Format = value
+	return nil
+}
+
+func flagOutput(cfg LoggerConfig) string {
+	switch output := cfg.Outputs[0]; {
+	case output.File != nil:
+		return output.File.Path
+	case output.Destination != "":
+		return output.Destination
+	}
+	return "stdout"
+}
+
+func setFlagOutput(cfg *LoggerConfig, value string) error {
+	output := &cfg.Outputs[0]
+	switch strings.ToLower(value) {
+	case "stdout", "stderr":
+		output.Destination, output.File = strings.ToLower(value), nil
+	default:
+		output.Destination, output.File = "", &FileConfig{Path: value}
+	}
+	return nil
+}
+
+func flagCaller(cfg LoggerConfig) string {
+	return strconv.FormatBool(cfg.Caller)
+}
+
+func setFlagCaller(cfg *LoggerConfig, value string) error {
+	caller, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	cfg.Caller = caller
+	return nil
+}
@@ -0,0 +1,97 @@
+package logos
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLevel(t *testing.T) {
+	SetLevelName(Level(9), "Audit")
+	t.Cleanup(func() { UnregisterLevel(Level(9)) })
+
+	for name, want := range map[string]Level{"debug": LevelDebug, " WARN ": LevelWarn, "audit": Level(9), "12": Level(12)} {
+		level, err := ParseLevel(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, level, name)
+	}
+	_, err := ParseLevel("loud")
+	assert.EqualError(t, err, `unknown level "loud"`)
+
+	var cfg struct {
+		Level  Level  `json:"level"`
+		Format Format `json:"format"`
+		Levels []Level
+	}
+	assert.NoError(t, json.Unmarshal([]byte(`{"level": "AUDIT", "format": "json", "Levels": ["info", 2, "12"]}`), &cfg))
+	assert.Equal(t, Level(9), cfg.Level)
+	assert.Equal(t, FormatJSON, cfg.Format)
+	assert.Equal(t, []Level{LevelInfo, LevelError, Level(12)}, cfg.Levels)
+
+	b, err := json.Marshal(cfg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"level": "Audit", "format": "JSON", "Levels": ["info", "error", "level(12)"]}`, string(b))
+
+	assert.Error(t, json.Unmarshal([]byte(`{"level": "loud"}`), &cfg))
+	assert.Error(t, json.Unmarshal([]byte(`{"format": "xml"}`), &cfg))
+	_, err = Format(99).MarshalText()
+	assert.Error(t, err)
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"json": FormatJSON, "Console": FormatConsole, "ECS": FormatECS} {
+		format, err := ParseFormat(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, format, name)
+	}
+	_, err := ParseFormat("xml")
+	assert.EqualError(t, err, `unknown format "xml"`)
+}
+
+func TestLevel_FlagValue(t *testing.T) {
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	level, format := LevelInfo, FormatText
+	fs.Var(&level, "level", "")
+	fs.Var(&format, "format", "")
+
+	assert.NoError(t, fs.Parse([]string{"-level", "error", "-format", "gcp"}))
+	assert.Equal(t, LevelError, level)
+	assert.Equal(t, FormatGCP, format)
+	assert.Error(t, fs.Parse([]string{"-level", "loud"}))
+}
+
+func TestRegisterFlags(t *testing.T) {
+	keepDefaultLogger(t)
+	path := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv("LOG_LEVEL", "error")
+
+	fs := flag.NewFlagSet("app", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	RegisterFlags(fs)
+	assert.Equal(t, "error", fs.Lookup("log-level").Value.String())
+	assert.Equal(t, "stdout", fs.Lookup("log-output").Value.String())
+
+	assert.NoError(t, fs.Parse([]string{"-log-level=info", "-log-format", "ecs", "-log-output", path, "-log-caller"}))
+	assert.Equal(t, LevelInfo, getDefaultLogger().GetLevel())
+	Info("hello")
+	Debug("dropped")
+	assert.NoError(t, fs.Set("log-output", "stdout")) // Closes the file
+	assert.Equal(t, "ecs", fs.Lookup("log-format").Value.String())
+
+	b, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(b)), "\n")
+	assert.Len(t, lines, 1)
+	assert.Contains(t, lines[0], `"message":"hello"`)
+	assert.Contains(t, lines[0], "flags_test.go")
+
+	assert.Error(t, fs.Parse([]string{"-log-format", "xml"}))
+	assert.Equal(t, "ecs", fs.Lookup("log-format").Value.String())
+}
@@ -1,5 +1,12 @@
 package logos
 
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/goodblaster/errors"
+)
+
 // Format represents the output format used by the logger.
 type Format int
 
@@ -18,6 +25,12 @@ const (
 	FormatText
 	// FormatConsole outputs logs as colored text suitable for terminals.
 	FormatConsole
+	// FormatGCP outputs logs in the structured JSON format parsed by Google Cloud Logging.
+	FormatGCP
+	// FormatOTel outputs logs as OpenTelemetry LogRecords in the OTLP JSON encoding.
+	FormatOTel
+	// FormatECS outputs logs in the Elastic Common Schema JSON shape.
+	FormatECS
 )
 
 // Formats is the list of all supported output formats.
@@ -25,6 +38,9 @@ var Formats = []Format{
 	FormatJSON,
 	FormatText,
 	FormatConsole,
+	FormatGCP,
+	FormatOTel,
+	FormatECS,
 }
 
 // FormatNames maps Format values to their string identifiers.
@@ -32,4 +48,68 @@ var FormatNames = map[Format]string{
 	FormatJSON:    "JSON",
 	FormatText:    "TEXT",
 	FormatConsole: "CONSOLE",
+	FormatGCP:     "GCP",
+	FormatOTel:    "OTEL",
+	FormatECS:     "ECS",
+}
+
+// lookupFormat returns the format with the given name, matched case-insensitively.
+func lookupFormat(name string) (Format, bool) {
+	for format, formatName := range FormatNames {
+		if strings.EqualFold(formatName, strings.TrimSpace(name)) {
+			return format, true
+		}
+	}
+	return 0, false
+}
+
+// ParseFormat returns the format with the given name, such as "json" or "CONSOLE",
+// matched case-insensitively against FormatNames.
+func ParseFormat(name string) (Format, error) {
+	if format, ok := lookupFormat(name); ok {
+		return format, nil
+	}
+	return 0, errors.New("unknown format %q", name)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (f Format) MarshalText() ([]byte, error) {
+	name, ok := FormatNames[f]
+	if !ok {
+		return nil, errors.New("unknown format %d", int(f))
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using ParseFormat.
+func (f *Format) UnmarshalText(text []byte) error {
+	parsed, err := ParseFormat(string(text))
+	if err != nil {
+		return err
+	}
+	*f = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing the format as a string.
+func (f Format) MarshalJSON() ([]byte, error) {
+	text, err := f.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting a format name.
+func (f *Format) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return errors.Wrap(err, "format must be a string")
+	}
+	return f.UnmarshalText([]byte(name))
+}
+
+// Set implements flag.Value using ParseFormat.
+func (f *Format) Set(name string) error {
+	return f.UnmarshalText([]byte(name))
 }
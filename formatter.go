@@ -1,6 +1,7 @@
 package logos
 
 import (
+	"io"
 	"time"
 )
 
@@ -11,11 +12,19 @@ type Formatter interface {
 }
 
 // Config defines the configuration for a Formatter, such as a custom timestamp function,
-// level names, and colors. If LevelNames or LevelColors are nil, the global defaults will be used.
+// level names, and colors. Levels missing from LevelNames or LevelColors are looked up in
+// LevelRegistry, or in the package registry (see RegisterLevel) if it is nil.
 type Config struct {
-	Timestamp   func() string
-	LevelNames  map[Level]string // Optional: custom level names. Falls back to global LevelNames if nil.
-	LevelColors map[Level]Color  // Optional: custom level colors. Falls back to global LevelColors if nil.
+	Timestamp     func() string
+	LevelNames    map[Level]string // Optional: custom level names, taking precedence over the registry.
+	LevelColors   map[Level]Color  // Optional: custom level colors, taking precedence over the registry.
+	LevelRegistry *LevelRegistry   // Optional: level names, colors and severities. Defaults to the package registry.
+	GCPProjectID  string           // Optional: GCP project used to qualify trace IDs in GCPFormatter output.
+	JSON          JSONConfig       // Optional: key names and encodings for JSONFormatter output.
+	MaxFieldDepth int              // Optional: maximum nesting of a field value. Defaults to DefaultMaxFieldDepth.
+	MaxFieldSize  int              // Optional: maximum encoded size of a field value in bytes. Defaults to DefaultMaxFieldSize.
+	Theme         *Theme           // Optional: console styles. Only the level is colored if nil.
+	ColorProfile  ColorProfile     // Optional: color capability for Theme styles. Detected from the environment if zero.
 }
 
 // DefaultConfig is the fallback configuration using the DefaultTimestamp function.
@@ -37,6 +46,12 @@ func NewFormatterWithConfig(format Format, cfg Config) Formatter {
 		return NewTextFormatter(cfg)
 	case FormatConsole:
 		return NewConsoleFormatter(cfg)
+	case FormatGCP:
+		return NewGCPFormatter(cfg)
+	case FormatOTel:
+		return NewOTelFormatter(cfg)
+	case FormatECS:
+		return NewECSFormatter(cfg)
 	}
 	panic("unknown format")
 }
@@ -63,3 +78,24 @@ func TextFormatter() Formatter {
 func ConsoleFormatter() Formatter {
 	return NewConsoleFormatter(DefaultConfig)
 }
+
+// AutoConsoleFormatter returns a colorized console formatter if colors are enabled for w,
+// or a plain text formatter otherwise, using the default configuration.
+func AutoConsoleFormatter(w io.Writer) Formatter {
+	return NewAutoConsoleFormatter(w, DefaultConfig)
+}
+
+// GCPFormatter returns a new Cloud Logging JSON formatter with the default configuration.
+func GCPFormatter() Formatter {
+	return NewGCPFormatter(DefaultConfig)
+}
+
+// OTelFormatter returns a new OpenTelemetry LogRecord formatter with the default configuration.
+func OTelFormatter() Formatter {
+	return NewOTelFormatter(DefaultConfig)
+}
+
+// ECSFormatter returns a new Elastic Common Schema JSON formatter with the default configuration.
+func ECSFormatter() Formatter {
+	return NewECSFormatter(DefaultConfig)
+}
@@ -1,24 +1,45 @@
 package logos
 
 import (
-	"encoding/json"
 	"fmt"
+	"io"
 	"slices"
+	"sort"
 	"strings"
 )
 
 // consoleFormatter is a log formatter that adds ANSI color codes for terminal output.
 type consoleFormatter struct {
-	cfg Config
+	cfg     Config
+	profile ColorProfile // Color capability used to render cfg.Theme
 }
 
 // NewConsoleFormatter creates a new consoleFormatter using the provided configuration.
+// If cfg.Theme is set, its styles are rendered for cfg.ColorProfile, which is detected
+// from the environment when left as ColorProfileAuto.
 func NewConsoleFormatter(cfg Config) Formatter {
-	return &consoleFormatter{cfg: cfg}
+	profile := cfg.ColorProfile
+	if profile == ColorProfileAuto {
+		profile = DetectColorProfile()
+	}
+	return &consoleFormatter{cfg: cfg, profile: profile}
+}
+
+// NewAutoConsoleFormatter returns a console formatter when colored output is enabled for w,
+// as decided by ColorEnabled, and a plain text formatter with the same layout otherwise.
+func NewAutoConsoleFormatter(w io.Writer, cfg Config) Formatter {
+	if ColorEnabled(w) {
+		return NewConsoleFormatter(cfg)
+	}
+	return NewTextFormatter(cfg)
 }
 
 // Format renders the log entry as a colored string using ANSI escape codes for terminal output.
 func (f consoleFormatter) Format(level Level, entry Entry) string {
+	if f.cfg.Theme != nil {
+		return f.formatThemed(level, entry, f.cfg.Theme)
+	}
+
 	// ANSI color codes - use config with fallback to globals
 	textColor := GetLevelColor(level, &f.cfg)
 
@@ -28,15 +49,10 @@ func (f consoleFormatter) Format(level Level, entry Entry) string {
 		tuples = append(tuples, fmt.Sprintf("error=%s%q%s", textColor, errMsg, ColorReset))
 	}
 
-	for key, value := range entry.Fields {
-		b, err := json.Marshal(value)
-		if err != nil {
-			// If marshal fails, include an error indicator instead of silently failing
-			tuples = append(tuples, fmt.Sprintf("%s=<marshal_error>", key))
-		} else {
-			tuples = append(tuples, fmt.Sprintf("%s=%v", key, string(b)))
-		}
-	}
+	forEachField(entry.Fields, func(key string, value any) {
+		// Values that cannot be encoded are replaced by a marker instead of being dropped
+		tuples = append(tuples, fmt.Sprintf("%s=%s", key, encodeFieldString(value, &f.cfg)))
+	})
 	slices.Sort(tuples)
 
 	// If there are tuples, add a tab to separate them from the message
@@ -51,3 +67,56 @@ func (f consoleFormatter) Format(level Level, entry Entry) string {
 		tupleString,
 		entry.Msg)
 }
+
+// formatThemed renders the log entry with the same layout as Format, styling the timestamp,
+// level, field keys, field values (by type), errors and message from the theme.
+func (f consoleFormatter) formatThemed(level Level, entry Entry, theme *Theme) string {
+	profile := f.profile
+	levelName := GetLevelName(level, &f.cfg)
+
+	levelText := levelName
+	if style, ok := theme.levelStyle(level); ok {
+		levelText = style.Render(levelName, profile)
+	} else if profile != ColorProfileNone {
+		levelText = string(GetLevelColor(level, &f.cfg)) + levelName + string(ColorReset)
+	}
+
+	type tuple struct {
+		key  string
+		text string
+	}
+	var tuples []tuple
+	if entry.Error != nil {
+		tuples = append(tuples, tuple{
+			key:  "error",
+			text: theme.Key.Render("error", profile) + "=" + theme.Error.Render(fmt.Sprintf("%q", entry.Error.Error()), profile),
+		})
+	}
+
+	forEachField(entry.Fields, func(key string, value any) {
+		var valueText string
+		if b, fieldErr := encodeField(value, &f.cfg); fieldErr != nil {
+			valueText = theme.Error.Render(fieldErr.String(), profile)
+		} else {
+			valueText = theme.valueStyle(string(b)).Render(string(b), profile)
+		}
+		tuples = append(tuples, tuple{key: key, text: theme.Key.Render(key, profile) + "=" + valueText})
+	})
+	sort.Slice(tuples, func(i, j int) bool { return tuples[i].key < tuples[j].key })
+
+	// If there are tuples, add a tab to separate them from the message
+	var tupleString string
+	if len(tuples) > 0 {
+		texts := make([]string, len(tuples))
+		for i, t := range tuples {
+			texts[i] = t.text
+		}
+		tupleString = strings.Join(texts, " ") + "\t"
+	}
+
+	return fmt.Sprintf("%s\t%s\t%s%s",
+		theme.Timestamp.Render(f.cfg.Timestamp(), profile),
+		levelText,
+		tupleString,
+		theme.Message.Render(entry.Msg, profile))
+}
@@ -0,0 +1,166 @@
+package logos
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// ECSVersion is the Elastic Common Schema version written in the ecs.version key.
+const ECSVersion = "1.6.0"
+
+// ecsReservedKeys are the top-level keys written by ecsFormatter. User fields with
+// the same name are written with a "fields." prefix instead of overwriting them.
+var ecsReservedKeys = map[string]bool{
+	"@timestamp":                true,
+	"log.level":                 true,
+	"message":                   true,
+	"ecs.version":               true,
+	"log.logger":                true,
+	"error.message":             true,
+	"trace.id":                  true,
+	"span.id":                   true,
+	"log.origin.file.name":      true,
+	"log.origin.file.line":      true,
+	"log.origin.function":       true,
+	"http.request.method":       true,
+	"url.original":              true,
+	"http.response.status_code": true,
+	"http.request.body.bytes":   true,
+	"http.response.body.bytes":  true,
+	"user_agent.original":       true,
+	"client.ip":                 true,
+	"server.ip":                 true,
+	"http.request.referrer":     true,
+	"http.version":              true,
+	"event.duration":            true,
+}
+
+// ecsFormatter is a log formatter that outputs the Elastic Common Schema JSON shape
+// written by the ecs-logging libraries.
+type ecsFormatter struct {
+	cfg Config
+}
+
+// NewECSFormatter creates a new ecsFormatter using the provided configuration.
+func NewECSFormatter(cfg Config) Formatter {
+	return &ecsFormatter{cfg: cfg}
+}
+
+// Format renders the log entry as an ECS JSON line, using dotted top-level keys.
+// Trace, span and HTTP request fields are moved to their ECS fields and caller data is
+// written as log.origin; all other fields are written at the top level.
+func (f ecsFormatter) Format(level Level, entry Entry) string {
+	t := entry.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	obj := &jsonObject{}
+	obj.add("@timestamp", t.UTC().Format(time.RFC3339Nano))
+	obj.add("log.level", GetLevelName(level, &f.cfg))
+	obj.add("message", entry.Msg)
+	obj.add("ecs.version", ECSVersion)
+	if entry.Name != "" {
+		obj.add("log.logger", entry.Name)
+	}
+	if entry.Error != nil {
+		obj.add("error.message", entry.Error.Error())
+	}
+	if traceID, ok := entry.Fields[FieldTraceID]; ok {
+		obj.addField("trace.id", traceID, &f.cfg)
+	}
+	if spanID, ok := entry.Fields[FieldSpanID]; ok {
+		obj.addField("span.id", spanID, &f.cfg)
+	}
+	if entry.Caller != nil {
+		obj.add("log.origin.file.name", entry.Caller.File)
+		obj.add("log.origin.file.line", entry.Caller.Line)
+		if entry.Caller.Function != "" {
+			obj.add("log.origin.function", entry.Caller.Function)
+		}
+	}
+
+	keys := make([]string, 0, len(entry.Fields))
+	for key := range entry.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := entry.Fields[key]
+		switch key {
+		case FieldTraceID, FieldSpanID:
+			continue
+		case FieldHTTPRequest:
+			if f.addHTTPRequest(obj, value) {
+				continue
+			}
+		}
+
+		if ecsReservedKeys[key] {
+			key = "fields." + key
+		}
+		obj.addField(key, value, &f.cfg)
+	}
+
+	b, err := obj.bytes()
+	if err != nil {
+		errorObj := &jsonObject{}
+		errorObj.add("@timestamp", t.UTC().Format(time.RFC3339Nano))
+		errorObj.add("log.level", GetLevelName(LevelError, &f.cfg))
+		errorObj.add("message", "[LOG ERROR: failed to marshal entry]")
+		errorObj.add("ecs.version", ECSVersion)
+		errorObj.add("error.message", err.Error())
+		if errorBytes, innerErr := errorObj.bytes(); innerErr == nil {
+			return string(errorBytes)
+		}
+		return `{"log.level":"error","message":"[LOG ERROR: catastrophic marshal failure]"}`
+	}
+
+	return string(b)
+}
+
+// addHTTPRequest writes an HTTPRequest field value as ECS http, url, user_agent, client
+// and event fields, reporting whether value was an HTTPRequest.
+func (f ecsFormatter) addHTTPRequest(obj *jsonObject, value any) bool {
+	var req HTTPRequest
+	switch v := value.(type) {
+	case HTTPRequest:
+		req = v
+	case *HTTPRequest:
+		if v == nil {
+			return false
+		}
+		req = *v
+	default:
+		return false
+	}
+
+	addString := func(key, value string) {
+		if value != "" {
+			obj.add(key, value)
+		}
+	}
+	addString("http.request.method", req.Method)
+	addString("url.original", req.URL)
+	if req.Status > 0 {
+		obj.add("http.response.status_code", req.Status)
+	}
+	if req.RequestSize > 0 {
+		obj.add("http.request.body.bytes", req.RequestSize)
+	}
+	if req.ResponseSize > 0 {
+		obj.add("http.response.body.bytes", req.ResponseSize)
+	}
+	addString("user_agent.original", req.UserAgent)
+	addString("client.ip", req.RemoteIP)
+	addString("server.ip", req.ServerIP)
+	addString("http.request.referrer", req.Referer)
+	addString("http.version", strings.TrimPrefix(req.Protocol, "HTTP/"))
+	if req.Latency > 0 {
+		// ECS durations are in nanoseconds
+		obj.add("event.duration", req.Latency.Nanoseconds())
+	}
+	return true
+}
@@ -0,0 +1,71 @@
+package logos
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goodblaster/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestECSFormatter(t *testing.T) {
+	when := time.Date(2024, 5, 6, 7, 8, 9, 123000000, time.UTC)
+	line := ECSFormatter().Format(LevelWarn, Entry{
+		Msg:  "Test",
+		Time: when,
+		Name: "api",
+		Fields: Fields{
+			FieldTraceID:      "4bf92f3577b34da6a3ce929d0e0e4736",
+			FieldSpanID:       "00f067aa0ba902b7",
+			FieldTraceSampled: true,
+			"user":            "alice",
+			"message":         "user supplied",
+		},
+		Error:  errors.New("boom"),
+		Caller: &Caller{File: "main.go", Line: 42, Function: "main.run"},
+	})
+	assert.True(t, strings.HasPrefix(line, `{"@timestamp":"2024-05-06T07:08:09.123Z","log.level":"warn","message":"Test","ecs.version":"`), line)
+
+	var m map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(line), &m))
+	assert.Equal(t, "api", m["log.logger"])
+	assert.Equal(t, "boom", m["error.message"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", m["trace.id"])
+	assert.Equal(t, "00f067aa0ba902b7", m["span.id"])
+	assert.Equal(t, true, m[FieldTraceSampled])
+	assert.Equal(t, "main.go", m["log.origin.file.name"])
+	assert.Equal(t, float64(42), m["log.origin.file.line"])
+	assert.Equal(t, "alice", m["user"])
+	assert.Equal(t, "user supplied", m["fields.message"])
+	assert.Nil(t, m[FieldTraceID])
+}
+
+func TestECSFormatter_HTTPRequest(t *testing.T) {
+	line := ECSFormatter().Format(LevelInfo, Entry{
+		Msg: "GET /users 200",
+		Fields: Fields{FieldHTTPRequest: HTTPRequest{
+			Method:       "GET",
+			URL:          "/users",
+			Status:       200,
+			ResponseSize: 512,
+			UserAgent:    "curl/8.0",
+			RemoteIP:     "10.0.0.1",
+			Protocol:     "HTTP/1.1",
+			Latency:      1500 * time.Millisecond,
+		}},
+	})
+
+	var m map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(line), &m))
+	assert.Equal(t, "GET", m["http.request.method"])
+	assert.Equal(t, "/users", m["url.original"])
+	assert.Equal(t, float64(200), m["http.response.status_code"])
+	assert.Equal(t, float64(512), m["http.response.body.bytes"])
+	assert.Equal(t, "curl/8.0", m["user_agent.original"])
+	assert.Equal(t, "10.0.0.1", m["client.ip"])
+	assert.Equal(t, "1.1", m["http.version"])
+	assert.Equal(t, float64(1500000000), m["event.duration"])
+	assert.Nil(t, m[FieldHTTPRequest])
+}
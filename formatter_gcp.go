@@ -0,0 +1,214 @@
+package logos
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Keys defined by the Cloud Logging structured logging format.
+const (
+	gcpKeySourceLocation = "logging.googleapis.com/sourceLocation"
+	gcpKeyTrace          = "logging.googleapis.com/trace"
+	gcpKeySpanID         = "logging.googleapis.com/spanId"
+	gcpKeyTraceSampled   = "logging.googleapis.com/trace_sampled"
+)
+
+// gcpReservedKeys are the top-level keys written by gcpFormatter. User fields with
+// the same name are written with a "fields." prefix instead of overwriting them.
+var gcpReservedKeys = map[string]bool{
+	"severity":           true,
+	"message":            true,
+	"time":               true,
+	"error":              true,
+	"httpRequest":        true,
+	gcpKeySourceLocation: true,
+	gcpKeyTrace:          true,
+	gcpKeySpanID:         true,
+	gcpKeyTraceSampled:   true,
+}
+
+// GCPSeverities sets the Cloud Logging severity of specific levels. Other levels use their
+// syslog severity (see LevelSpec.SyslogSeverity), which Cloud Logging severities follow.
+var GCPSeverities = map[Level]string{
+	LevelPrint: "DEFAULT",
+}
+
+// gcpSyslogSeverities names the Cloud Logging severity of each syslog severity.
+var gcpSyslogSeverities = [...]string{
+	SyslogEmergency:     "EMERGENCY",
+	SyslogAlert:         "ALERT",
+	SyslogCritical:      "CRITICAL",
+	SyslogError:         "ERROR",
+	SyslogWarning:       "WARNING",
+	SyslogNotice:        "NOTICE",
+	SyslogInformational: "INFO",
+	SyslogDebug:         "DEBUG",
+}
+
+// gcpFormatter is a log formatter that outputs the JSON shape parsed by the Cloud Logging agents.
+type gcpFormatter struct {
+	cfg Config
+}
+
+// NewGCPFormatter creates a new gcpFormatter using the provided configuration.
+// If cfg.GCPProjectID is set, trace IDs are written as "projects/<id>/traces/<trace>".
+func NewGCPFormatter(cfg Config) Formatter {
+	return &gcpFormatter{cfg: cfg}
+}
+
+// gcpSourceLocation is the payload of the logging.googleapis.com/sourceLocation key.
+type gcpSourceLocation struct {
+	File     string `json:"file"`
+	Line     string `json:"line"`
+	Function string `json:"function,omitempty"`
+}
+
+// gcpHTTPRequest mirrors the Cloud Logging HttpRequest message.
+type gcpHTTPRequest struct {
+	RequestMethod string `json:"requestMethod,omitempty"`
+	RequestURL    string `json:"requestUrl,omitempty"`
+	RequestSize   string `json:"requestSize,omitempty"`
+	Status        int    `json:"status,omitempty"`
+	ResponseSize  string `json:"responseSize,omitempty"`
+	UserAgent     string `json:"userAgent,omitempty"`
+	RemoteIP      string `json:"remoteIp,omitempty"`
+	ServerIP      string `json:"serverIp,omitempty"`
+	Referer       string `json:"referer,omitempty"`
+	Latency       string `json:"latency,omitempty"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+// Format renders the log entry as a Cloud Logging structured JSON line.
+// Trace, span and HTTP request fields are moved to their dedicated keys;
+// all other fields are written at the top level so they land in jsonPayload.
+func (f gcpFormatter) Format(level Level, entry Entry) string {
+	t := entry.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	m := map[string]any{
+		"severity": gcpSeverity(level, &f.cfg),
+		"message":  entry.Msg,
+		"time":     t.UTC().Format(time.RFC3339Nano),
+	}
+
+	if entry.Error != nil {
+		m["error"] = entry.Error.Error()
+	}
+
+	if entry.Caller != nil {
+		m[gcpKeySourceLocation] = gcpSourceLocation{
+			File:     entry.Caller.File,
+			Line:     strconv.Itoa(entry.Caller.Line),
+			Function: entry.Caller.Function,
+		}
+	}
+
+	for key, value := range entry.Fields {
+		switch key {
+		case FieldTraceID:
+			m[gcpKeyTrace] = f.trace(value)
+			continue
+		case FieldSpanID:
+			m[gcpKeySpanID] = fmt.Sprint(value)
+			continue
+		case FieldTraceSampled:
+			if sampled, ok := value.(bool); ok {
+				m[gcpKeyTraceSampled] = sampled
+				continue
+			}
+		case FieldHTTPRequest:
+			if req, ok := gcpRequest(value); ok {
+				m["httpRequest"] = req
+				continue
+			}
+		}
+
+		if gcpReservedKeys[key] {
+			key = "fields." + key
+		}
+		// Encode fields one at a time so a bad value doesn't lose the whole entry
+		if b, fieldErr := encodeField(value, &f.cfg); fieldErr != nil {
+			m[key] = fieldErr
+		} else {
+			m[key] = json.RawMessage(b)
+		}
+	}
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		errorMsg := map[string]any{
+			"severity": "ERROR",
+			"message":  "[LOG ERROR: failed to marshal entry]",
+			"time":     m["time"],
+			"error":    err.Error(),
+		}
+		if errorBytes, innerErr := json.Marshal(errorMsg); innerErr == nil {
+			return string(errorBytes)
+		}
+		return `{"severity":"ERROR","message":"[LOG ERROR: catastrophic marshal failure]"}`
+	}
+
+	return string(b)
+}
+
+// trace formats a trace ID as the full resource name when a project ID is configured.
+func (f gcpFormatter) trace(value any) string {
+	traceID := fmt.Sprint(value)
+	if f.cfg.GCPProjectID == "" {
+		return traceID
+	}
+	return "projects/" + f.cfg.GCPProjectID + "/traces/" + traceID
+}
+
+// gcpSeverity returns the Cloud Logging severity for a level.
+func gcpSeverity(level Level, cfg *Config) string {
+	if severity, ok := GCPSeverities[level]; ok {
+		return severity
+	}
+	severity := cfg.levels().SyslogSeverity(level)
+	if severity < SyslogEmergency || severity > SyslogDebug {
+		return "DEFAULT"
+	}
+	return gcpSyslogSeverities[severity]
+}
+
+// gcpRequest converts an HTTPRequest field value into the Cloud Logging HttpRequest shape.
+func gcpRequest(value any) (gcpHTTPRequest, bool) {
+	var req HTTPRequest
+	switch v := value.(type) {
+	case HTTPRequest:
+		req = v
+	case *HTTPRequest:
+		if v == nil {
+			return gcpHTTPRequest{}, false
+		}
+		req = *v
+	default:
+		return gcpHTTPRequest{}, false
+	}
+
+	out := gcpHTTPRequest{
+		RequestMethod: req.Method,
+		RequestURL:    req.URL,
+		Status:        req.Status,
+		UserAgent:     req.UserAgent,
+		RemoteIP:      req.RemoteIP,
+		ServerIP:      req.ServerIP,
+		Referer:       req.Referer,
+		Protocol:      req.Protocol,
+	}
+	if req.RequestSize > 0 {
+		out.RequestSize = strconv.FormatInt(req.RequestSize, 10)
+	}
+	if req.ResponseSize > 0 {
+		out.ResponseSize = strconv.FormatInt(req.ResponseSize, 10)
+	}
+	if req.Latency > 0 {
+		out.Latency = strconv.FormatFloat(req.Latency.Seconds(), 'f', -1, 64) + "s"
+	}
+	return out, true
+}
@@ -0,0 +1,118 @@
+package logos
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGCPFormatter_Severity(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := NewLogger(LevelTrace-1, GCPFormatter(), buf)
+
+	tests := map[Level]string{
+		LevelDebug:     "DEBUG",
+		LevelInfo:      "INFO",
+		LevelNotice:    "NOTICE",
+		LevelWarn:      "WARNING",
+		LevelError:     "ERROR",
+		LevelAlert:     "ALERT",
+		LevelEmergency: "EMERGENCY",
+		LevelPrint:     "DEFAULT",
+		LevelTrace - 1: "DEBUG",
+		LevelPanic + 5: "EMERGENCY",
+	}
+	for level, severity := range tests {
+		log.Log(level, "test")
+		m := Map(buf)
+		assert.Equal(t, severity, m["severity"], "level %d", level)
+		assert.Equal(t, "test", m["message"])
+		assert.Nil(t, m["level"])
+	}
+}
+
+func TestGCPFormatter_Time(t *testing.T) {
+	fmtr := NewGCPFormatter(DefaultConfig)
+	when := time.Date(2024, 5, 6, 7, 8, 9, 123000000, time.UTC)
+	line := fmtr.Format(LevelInfo, Entry{Msg: "Test", Time: when})
+
+	var m map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(line), &m))
+	assert.Equal(t, "2024-05-06T07:08:09.123Z", m["time"])
+}
+
+func TestGCPFormatter_TraceAndFields(t *testing.T) {
+	fmtr := NewGCPFormatter(Config{GCPProjectID: "my-project"})
+	line := fmtr.Format(LevelInfo, Entry{
+		Msg: "Test",
+		Fields: Fields{
+			FieldTraceID:      "4bf92f3577b34da6a3ce929d0e0e4736",
+			FieldSpanID:       "00f067aa0ba902b7",
+			FieldTraceSampled: true,
+			"user":            "alice",
+			"severity":        "user supplied",
+		},
+		Error: assert.AnError,
+	})
+
+	var m map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(line), &m))
+	assert.Equal(t, "projects/my-project/traces/4bf92f3577b34da6a3ce929d0e0e4736", m["logging.googleapis.com/trace"])
+	assert.Equal(t, "00f067aa0ba902b7", m["logging.googleapis.com/spanId"])
+	assert.Equal(t, true, m["logging.googleapis.com/trace_sampled"])
+	assert.Equal(t, "alice", m["user"])
+	assert.Equal(t, "INFO", m["severity"])
+	assert.Equal(t, "user supplied", m["fields.severity"])
+	assert.Equal(t, assert.AnError.Error(), m["error"])
+	assert.Nil(t, m[FieldTraceID])
+}
+
+func TestGCPFormatter_SourceLocation(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := NewLogger(LevelDebug, GCPFormatter(), buf).WithCaller(true)
+
+	log.Info("with caller")
+	m := Map(buf)
+	loc, ok := m["logging.googleapis.com/sourceLocation"].(map[string]any)
+	if assert.True(t, ok) {
+		assert.Contains(t, loc["file"], "formatter_gcp_test.go")
+		assert.NotEmpty(t, loc["line"])
+		assert.Contains(t, loc["function"], "TestGCPFormatter_SourceLocation")
+	}
+
+	// Without caller capture, no source location is written.
+	NewLogger(LevelDebug, GCPFormatter(), buf).Info("without caller")
+	m = Map(buf)
+	assert.Nil(t, m["logging.googleapis.com/sourceLocation"])
+}
+
+func TestGCPFormatter_HTTPRequest(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := NewLogger(LevelDebug, GCPFormatter(), buf)
+
+	log.With(FieldHTTPRequest, HTTPRequest{
+		Method:       "GET",
+		URL:          "/users/42",
+		Status:       200,
+		ResponseSize: 512,
+		UserAgent:    "curl/8.0",
+		RemoteIP:     "10.0.0.1",
+		Latency:      1500 * time.Millisecond,
+	}).Info("request served")
+
+	m := Map(buf)
+	req, ok := m["httpRequest"].(map[string]any)
+	if assert.True(t, ok) {
+		assert.Equal(t, "GET", req["requestMethod"])
+		assert.Equal(t, "/users/42", req["requestUrl"])
+		assert.EqualValues(t, 200, req["status"])
+		assert.Equal(t, "512", req["responseSize"])
+		assert.Equal(t, "curl/8.0", req["userAgent"])
+		assert.Equal(t, "10.0.0.1", req["remoteIp"])
+		assert.Equal(t, "1.5s", req["latency"])
+	}
+	assert.Nil(t, m[FieldHTTPRequest])
+}
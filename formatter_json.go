@@ -1,11 +1,90 @@
 package logos
 
 import (
+	"bytes"
 	"encoding/json"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/goodblaster/errors"
 )
 
+// TimeEncoding selects how jsonFormatter writes the entry timestamp.
+type TimeEncoding int
+
+const (
+	// TimeEncodingDefault writes the string returned by Config.Timestamp.
+	TimeEncodingDefault TimeEncoding = iota
+	// TimeEncodingRFC3339Nano writes the entry time as an RFC 3339 string with nanoseconds.
+	TimeEncodingRFC3339Nano
+	// TimeEncodingUnixSeconds writes the entry time as a number of seconds since the Unix epoch.
+	TimeEncodingUnixSeconds
+	// TimeEncodingUnixMillis writes the entry time as a number of milliseconds since the Unix epoch.
+	TimeEncodingUnixMillis
+	// TimeEncodingUnixNanos writes the entry time as a number of nanoseconds since the Unix epoch.
+	TimeEncodingUnixNanos
+)
+
+// LevelEncoding selects how jsonFormatter writes the entry level.
+type LevelEncoding int
+
+const (
+	// LevelEncodingName writes the level name, e.g. "info".
+	LevelEncodingName LevelEncoding = iota
+	// LevelEncodingUpper writes the upper-cased level name, e.g. "INFO".
+	LevelEncodingUpper
+	// LevelEncodingNumeric writes the numeric Level value, e.g. 0.
+	LevelEncodingNumeric
+)
+
+// JSONConfig controls the layout of jsonFormatter output. The zero value produces the
+// default layout: level, timestamp, error, trace_id, span_id, fields (nested) and msg.
+// The trace and span IDs are always written at the top level so log pipelines can
+// correlate entries with traces.
+type JSONConfig struct {
+	LevelKey        string        // Default: "level".
+	TimestampKey    string        // Default: "timestamp".
+	MessageKey      string        // Default: "msg".
+	ErrorKey        string        // Default: "error".
+	TraceIDKey      string        // Key for the FieldTraceID field. Default: "trace_id".
+	SpanIDKey       string        // Key for the FieldSpanID field. Default: "span_id".
+	FieldsKey       string        // Default: "fields". Unused when FlattenFields is set.
+	FlattenFields   bool          // Write fields at the top level instead of nesting them under FieldsKey.
+	CollisionPrefix string        // Prefix for flattened fields whose key is reserved. Default: "fields.".
+	TimeEncoding    TimeEncoding  // Default: TimeEncodingDefault.
+	LevelEncoding   LevelEncoding // Default: LevelEncodingName.
+}
+
+// withDefaults returns a copy of the JSONConfig with empty keys replaced by their defaults.
+func (c JSONConfig) withDefaults() JSONConfig {
+	if c.LevelKey == "" {
+		c.LevelKey = "level"
+	}
+	if c.TimestampKey == "" {
+		c.TimestampKey = "timestamp"
+	}
+	if c.MessageKey == "" {
+		c.MessageKey = "msg"
+	}
+	if c.ErrorKey == "" {
+		c.ErrorKey = "error"
+	}
+	if c.TraceIDKey == "" {
+		c.TraceIDKey = FieldTraceID
+	}
+	if c.SpanIDKey == "" {
+		c.SpanIDKey = FieldSpanID
+	}
+	if c.FieldsKey == "" {
+		c.FieldsKey = "fields"
+	}
+	if c.CollisionPrefix == "" {
+		c.CollisionPrefix = "fields."
+	}
+	return c
+}
+
 // jsonFormatter is a log formatter that outputs logs in JSON format.
 type jsonFormatter struct {
 	cfg Config
@@ -17,36 +96,74 @@ func NewJsonFormatter(cfg Config) Formatter {
 }
 
 // Format renders the log entry as a JSON string.
-// It includes the log level, timestamp, error (if any), fields, and message.
-// If marshaling fails, it returns an error message in JSON format instead of panicking.
+// It includes the log level, timestamp, error (if any), fields, and message, laid out
+// according to cfg.JSON. Fields are encoded one at a time, so a field that cannot be
+// marshaled is replaced by a FieldError without losing the rest of the entry.
+// If the entry itself cannot be marshaled, it returns an error message in JSON format
+// instead of panicking.
 func (f jsonFormatter) Format(level Level, entry Entry) string {
-	type JsonStruct struct {
-		Level     string         `json:"level"`
-		Timestamp string         `json:"timestamp"`
-		Error     error          `json:"error,omitempty"`
-		Fields    map[string]any `json:"fields,omitempty"`
-		Msg       string         `json:"msg"`
+	layout := f.cfg.JSON.withDefaults()
+
+	obj := &jsonObject{}
+	obj.add(layout.LevelKey, f.level(level, layout))
+	obj.add(layout.TimestampKey, f.timestamp(entry, layout))
+	if entry.Error != nil {
+		obj.add(layout.ErrorKey, entry.Error)
+	}
+	if traceID, ok := entry.Fields[FieldTraceID]; ok {
+		obj.addField(layout.TraceIDKey, traceID, &f.cfg)
+	}
+	if spanID, ok := entry.Fields[FieldSpanID]; ok {
+		obj.addField(layout.SpanIDKey, spanID, &f.cfg)
 	}
 
-	jsonStruct := JsonStruct{
-		Level:     GetLevelName(level, &f.cfg),
-		Timestamp: f.cfg.Timestamp(),
-		Error:     entry.Error,
-		Msg:       entry.Msg,
-		Fields:    entry.Fields,
+	if len(entry.Fields) > 0 {
+		keys := make([]string, 0, len(entry.Fields))
+		for key := range entry.Fields {
+			if key != FieldTraceID && key != FieldSpanID {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+
+		if layout.FlattenFields {
+			reserved := map[string]bool{
+				layout.LevelKey:     true,
+				layout.TimestampKey: true,
+				layout.MessageKey:   true,
+				layout.ErrorKey:     true,
+				layout.TraceIDKey:   true,
+				layout.SpanIDKey:    true,
+			}
+			for _, key := range keys {
+				name := key
+				if reserved[name] {
+					name = layout.CollisionPrefix + name
+				}
+				obj.addField(name, entry.Fields[key], &f.cfg)
+			}
+		} else {
+			fields := &jsonObject{}
+			for _, key := range keys {
+				fields.addField(key, entry.Fields[key], &f.cfg)
+			}
+			if fields.buf.Len() > 0 {
+				obj.addObject(layout.FieldsKey, fields)
+			}
+		}
 	}
 
-	b, err := json.Marshal(jsonStruct)
+	obj.add(layout.MessageKey, entry.Msg)
+
+	b, err := obj.bytes()
 	if err != nil {
 		// Instead of panicking, return an error message in JSON format
-		errorMsg := JsonStruct{
-			Level:     "error",
-			Timestamp: f.cfg.Timestamp(),
-			Error:     errors.Wrap(err, "failed to marshal log entry"),
-			Msg:       "[LOG ERROR: failed to marshal entry]",
-			Fields:    nil, // Don't include fields that might have caused the error
-		}
-		if errorBytes, innerErr := json.Marshal(errorMsg); innerErr == nil {
+		errorObj := &jsonObject{}
+		errorObj.add(layout.LevelKey, f.level(LevelError, layout))
+		errorObj.add(layout.TimestampKey, f.timestamp(entry, layout))
+		errorObj.add(layout.ErrorKey, errors.Wrap(err, "failed to marshal log entry"))
+		errorObj.add(layout.MessageKey, "[LOG ERROR: failed to marshal entry]")
+		if errorBytes, innerErr := errorObj.bytes(); innerErr == nil {
 			return string(errorBytes)
 		}
 		// If even the error message can't be marshaled, return a simple string
@@ -55,3 +172,128 @@ func (f jsonFormatter) Format(level Level, entry Entry) string {
 
 	return string(b)
 }
+
+// level returns the level value in the configured encoding.
+func (f jsonFormatter) level(level Level, layout JSONConfig) any {
+	switch layout.LevelEncoding {
+	case LevelEncodingUpper:
+		return strings.ToUpper(GetLevelName(level, &f.cfg))
+	case LevelEncodingNumeric:
+		return int(level)
+	default:
+		return GetLevelName(level, &f.cfg)
+	}
+}
+
+// timestamp returns the entry time in the configured encoding.
+func (f jsonFormatter) timestamp(entry Entry, layout JSONConfig) any {
+	if layout.TimeEncoding == TimeEncodingDefault {
+		return f.cfg.Timestamp()
+	}
+
+	t := entry.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	switch layout.TimeEncoding {
+	case TimeEncodingUnixSeconds:
+		return t.Unix()
+	case TimeEncodingUnixMillis:
+		return t.UnixMilli()
+	case TimeEncodingUnixNanos:
+		return t.UnixNano()
+	default:
+		return t.Format(time.RFC3339Nano)
+	}
+}
+
+// jsonObject builds a JSON object whose keys are written in insertion order.
+// The first marshal error is kept and reported by bytes.
+type jsonObject struct {
+	buf bytes.Buffer
+	err error
+}
+
+// add appends a key and its marshaled value to the object.
+func (o *jsonObject) add(key string, value any) {
+	if o.err != nil {
+		return
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		o.err = err
+		return
+	}
+	o.addRaw(key, b)
+}
+
+// addField appends a field value, replacing it with a FieldError if it cannot be encoded.
+func (o *jsonObject) addField(key string, value any, cfg *Config) {
+	if group, ok := value.(Group); ok {
+		if !isEmptyGroup(group) {
+			o.addObject(key, groupObject(group, cfg))
+		}
+		return
+	}
+
+	b, fieldErr := encodeField(value, cfg)
+	if fieldErr != nil {
+		o.add(key, fieldErr)
+		return
+	}
+	o.addRaw(key, b)
+}
+
+// groupObject encodes a group as a nested object, with each value encoded individually.
+func groupObject(group Group, cfg *Config) *jsonObject {
+	keys := make([]string, 0, len(group))
+	for key := range group {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	nested := &jsonObject{}
+	for _, key := range keys {
+		nested.addField(key, group[key], cfg)
+	}
+	return nested
+}
+
+// addObject appends a nested object.
+func (o *jsonObject) addObject(key string, nested *jsonObject) {
+	b, err := nested.bytes()
+	if err != nil {
+		o.err = err
+		return
+	}
+	o.addRaw(key, b)
+}
+
+// addRaw appends a key and an already-encoded value to the object.
+func (o *jsonObject) addRaw(key string, value []byte) {
+	if o.err != nil {
+		return
+	}
+
+	k, _ := json.Marshal(key)
+	if o.buf.Len() == 0 {
+		o.buf.WriteByte('{')
+	} else {
+		o.buf.WriteByte(',')
+	}
+	o.buf.Write(k)
+	o.buf.WriteByte(':')
+	o.buf.Write(value)
+}
+
+// bytes returns the completed object.
+func (o *jsonObject) bytes() ([]byte, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	if o.buf.Len() == 0 {
+		return []byte("{}"), nil
+	}
+	return append(o.buf.Bytes(), '}'), nil
+}
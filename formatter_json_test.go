@@ -3,6 +3,8 @@ package logos
 import (
 	"bytes"
 	"encoding/json"
+	"math"
+	"strings"
 	"testing"
 	"time"
 
@@ -147,13 +149,64 @@ func TestJsonFormatter_MarshalError(t *testing.T) {
 	circ := &circular{}
 	circ.Self = circ
 
-	// This should not panic, but should log an error message instead
-	log.With("circular", circ).Info("test with circular reference")
+	// This should not panic; only the offending field is replaced
+	log.With("circular", circ).With("ok", "value").Info("test with circular reference")
 
-	// Verify we got an error message instead of a panic
+	// Verify the rest of the entry is intact
 	m := Map(buf)
-	assert.Equal(t, "[LOG ERROR: failed to marshal entry]", m["msg"])
-	assert.Equal(t, "error", m["level"])
+	assert.Equal(t, "test with circular reference", m["msg"])
+	assert.Equal(t, "info", m["level"])
+	assert.Equal(t, "value", m.Field("ok"))
+	assert.Equal(t, map[string]any{
+		"marshal_error": "cycle detected via *logos.circular",
+		"type":          "*logos.circular",
+	}, m.Field("circular"))
+}
+
+func TestJsonFormatter_UnmarshalableFields(t *testing.T) {
+	fmtr := NewJsonFormatter(DefaultConfig)
+
+	fields := Fields{
+		"chan": make(chan int),
+		"func": func() {},
+		"nan":  math.NaN(),
+		"ok":   42,
+	}
+	line := fmtr.Format(LevelInfo, Entry{Msg: "Test", Fields: fields})
+
+	var m BMap
+	assert.NoError(t, json.Unmarshal([]byte(line), &m))
+	assert.Equal(t, "Test", m["msg"])
+	assert.EqualValues(t, 42, m.Field("ok"))
+	assert.Equal(t, "chan int", m.Field("chan").(map[string]any)["type"])
+	assert.Equal(t, "func()", m.Field("func").(map[string]any)["type"])
+	assert.Equal(t, "float64", m.Field("nan").(map[string]any)["type"])
+
+	// Flattened fields degrade the same way.
+	cfg := DefaultConfig
+	cfg.JSON.FlattenFields = true
+	line = NewJsonFormatter(cfg).Format(LevelInfo, Entry{Msg: "Test", Fields: fields})
+	assert.NoError(t, json.Unmarshal([]byte(line), &m))
+	assert.Equal(t, "chan int", m["chan"].(map[string]any)["type"])
+	assert.EqualValues(t, 42, m["ok"])
+}
+
+func TestJsonFormatter_FieldLimits(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.MaxFieldDepth = 2
+	cfg.MaxFieldSize = 32
+
+	line := NewJsonFormatter(cfg).Format(LevelInfo, Entry{Msg: "Test", Fields: Fields{
+		"shallow": map[string]any{"a": 1},
+		"deep":    map[string]any{"a": map[string]any{"b": map[string]any{"c": 1}}},
+		"big":     strings.Repeat("x", 100),
+	}})
+
+	var m BMap
+	assert.NoError(t, json.Unmarshal([]byte(line), &m))
+	assert.Equal(t, map[string]any{"a": float64(1)}, m.Field("shallow"))
+	assert.Contains(t, m.Field("deep").(map[string]any)["marshal_error"], "max depth of 2")
+	assert.Contains(t, m.Field("big").(map[string]any)["marshal_error"], "exceeds limit of 32 bytes")
 }
 
 func TestJsonFormatter_CustomConfig(t *testing.T) {
@@ -182,3 +235,101 @@ func TestJsonFormatter_CustomConfig(t *testing.T) {
 	m = Map(buf)
 	assert.Equal(t, "information", m["level"], "Should use custom level name")
 }
+
+func TestJsonFormatter_KeyNames(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.JSON = JSONConfig{
+		LevelKey:     "severity",
+		TimestampKey: "ts",
+		MessageKey:   "message",
+		ErrorKey:     "err",
+		FieldsKey:    "attrs",
+	}
+
+	line := NewJsonFormatter(cfg).Format(LevelInfo, Entry{
+		Msg:    "Test",
+		Fields: Fields{"key": "value"},
+		Error:  errors.New("boom"),
+	})
+
+	var m map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(line), &m))
+	assert.Equal(t, "info", m["severity"])
+	assert.NotEmpty(t, m["ts"])
+	assert.Equal(t, "Test", m["message"])
+	assert.Equal(t, []any{"boom"}, m["err"])
+	assert.Equal(t, map[string]any{"key": "value"}, m["attrs"])
+	for _, key := range []string{"level", "timestamp", "msg", "error", "fields"} {
+		assert.NotContains(t, m, key)
+	}
+
+	// Keys keep their default order.
+	assert.Regexp(t, `^\{"severity":.*,"ts":.*,"err":.*,"attrs":.*,"message":"Test"\}$`, line)
+}
+
+func TestJsonFormatter_FlattenFields(t *testing.T) {
+	cfg := DefaultConfig
+	cfg.JSON = JSONConfig{FlattenFields: true, MessageKey: "message"}
+
+	line := NewJsonFormatter(cfg).Format(LevelInfo, Entry{
+		Msg: "Test",
+		Fields: Fields{
+			"user":    "alice",
+			"level":   "user level",
+			"message": "user message",
+		},
+	})
+
+	var m map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(line), &m))
+	assert.Equal(t, "alice", m["user"])
+	assert.Equal(t, "info", m["level"])
+	assert.Equal(t, "Test", m["message"])
+	assert.Equal(t, "user level", m["fields.level"])
+	assert.Equal(t, "user message", m["fields.message"])
+	assert.NotContains(t, m, "fields")
+
+	// Custom collision prefix.
+	cfg.JSON.CollisionPrefix = "_"
+	line = NewJsonFormatter(cfg).Format(LevelInfo, Entry{Msg: "Test", Fields: Fields{"level": "x"}})
+	assert.NoError(t, json.Unmarshal([]byte(line), &m))
+	assert.Equal(t, "x", m["_level"])
+}
+
+func TestJsonFormatter_TimeEncoding(t *testing.T) {
+	when := time.Date(2024, 1, 2, 3, 4, 5, 678901234, time.UTC)
+	tests := []struct {
+		encoding TimeEncoding
+		want     any
+	}{
+		{TimeEncodingRFC3339Nano, "2024-01-02T03:04:05.678901234Z"},
+		{TimeEncodingUnixSeconds, json.Number("1704164645")},
+		{TimeEncodingUnixMillis, json.Number("1704164645678")},
+		{TimeEncodingUnixNanos, json.Number("1704164645678901234")},
+	}
+	for _, tt := range tests {
+		cfg := DefaultConfig
+		cfg.JSON.TimeEncoding = tt.encoding
+		line := NewJsonFormatter(cfg).Format(LevelInfo, Entry{Msg: "Test", Time: when})
+
+		var m map[string]any
+		decoder := json.NewDecoder(bytes.NewReader([]byte(line)))
+		decoder.UseNumber()
+		assert.NoError(t, decoder.Decode(&m))
+		assert.Equal(t, tt.want, m["timestamp"])
+	}
+}
+
+func TestJsonFormatter_LevelEncoding(t *testing.T) {
+	cfg := DefaultConfig
+
+	cfg.JSON.LevelEncoding = LevelEncodingUpper
+	line := NewJsonFormatter(cfg).Format(LevelWarn, Entry{Msg: "Test"})
+	assert.Contains(t, line, `"level":"WARN"`)
+
+	cfg.JSON.LevelEncoding = LevelEncodingNumeric
+	line = NewJsonFormatter(cfg).Format(LevelWarn, Entry{Msg: "Test"})
+	assert.Contains(t, line, `"level":1`)
+	line = NewJsonFormatter(cfg).Format(LevelDebug, Entry{Msg: "Test"})
+	assert.Contains(t, line, `"level":-1`)
+}
@@ -0,0 +1,254 @@
+package logos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// OpenTelemetry severity numbers for the start of each severity range.
+const (
+	OTelSeverityUnspecified = 0
+	OTelSeverityTrace       = 1
+	OTelSeverityDebug       = 5
+	OTelSeverityInfo        = 9
+	OTelSeverityWarn        = 13
+	OTelSeverityError       = 17
+	OTelSeverityFatal       = 21
+)
+
+// OTelSeverities mirrors the OpenTelemetry severities of the package registry, as LevelNames
+// does its names. Severities are set with LevelSpec.OTelSeverity; see LevelRegistry.OTelSeverity.
+//
+// Deprecated: Use LookupLevel.
+var OTelSeverities map[Level]int
+
+// otelMaxDepth bounds how deeply nested field values are converted to AnyValue.
+const otelMaxDepth = 8
+
+// otelFormatter is a log formatter that outputs one OpenTelemetry LogRecord per line,
+// using the OTLP JSON encoding. Pair it with an OTLPExporter to ship records to a collector.
+type otelFormatter struct {
+	cfg Config
+}
+
+// NewOTelFormatter creates a new otelFormatter using the provided configuration.
+func NewOTelFormatter(cfg Config) Formatter {
+	return &otelFormatter{cfg: cfg}
+}
+
+// otelLogRecord is the OTLP JSON encoding of a LogRecord.
+type otelLogRecord struct {
+	TimeUnixNano         string         `json:"timeUnixNano"`
+	ObservedTimeUnixNano string         `json:"observedTimeUnixNano"`
+	SeverityNumber       int            `json:"severityNumber"`
+	SeverityText         string         `json:"severityText"`
+	Body                 otelAnyValue   `json:"body"`
+	Attributes           []otelKeyValue `json:"attributes,omitempty"`
+	TraceID              string         `json:"traceId,omitempty"`
+	SpanID               string         `json:"spanId,omitempty"`
+	Flags                uint32         `json:"flags,omitempty"`
+}
+
+// otelKeyValue is the OTLP JSON encoding of a KeyValue.
+type otelKeyValue struct {
+	Key   string       `json:"key"`
+	Value otelAnyValue `json:"value"`
+}
+
+// otelAnyValue is the OTLP JSON encoding of an AnyValue. Exactly one field is set.
+type otelAnyValue struct {
+	StringValue *string        `json:"stringValue,omitempty"`
+	BoolValue   *bool          `json:"boolValue,omitempty"`
+	IntValue    *string        `json:"intValue,omitempty"`
+	DoubleValue *float64       `json:"doubleValue,omitempty"`
+	ArrayValue  *otelArray     `json:"arrayValue,omitempty"`
+	KvlistValue *otelKeyValues `json:"kvlistValue,omitempty"`
+}
+
+type otelArray struct {
+	Values []otelAnyValue `json:"values"`
+}
+
+type otelKeyValues struct {
+	Values []otelKeyValue `json:"values"`
+}
+
+// Format renders the log entry as an OTLP JSON LogRecord.
+// Fields become attributes, except trace_id and span_id which fill TraceId and SpanId,
+// and a boolean trace_sampled which sets the sampled bit of Flags.
+func (f otelFormatter) Format(level Level, entry Entry) string {
+	t := entry.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	record := otelLogRecord{
+		TimeUnixNano:         strconv.FormatInt(t.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+		SeverityNumber:       f.cfg.levels().OTelSeverity(level),
+		SeverityText:         GetLevelName(level, &f.cfg),
+		Body:                 otelString(entry.Msg),
+	}
+
+	keys := make([]string, 0, len(entry.Fields))
+	for key := range entry.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := entry.Fields[key]
+		switch key {
+		case FieldTraceID:
+			record.TraceID = fmt.Sprint(value)
+			continue
+		case FieldSpanID:
+			record.SpanID = fmt.Sprint(value)
+			continue
+		case FieldTraceSampled:
+			if sampled, ok := value.(bool); ok {
+				if sampled {
+					record.Flags = uint32(TraceFlagSampled)
+				}
+				continue
+			}
+		}
+		record.Attributes = append(record.Attributes, otelKeyValue{Key: key, Value: otelValue(value, 0)})
+	}
+
+	if entry.Error != nil {
+		record.Attributes = append(record.Attributes,
+			otelKeyValue{Key: "exception.message", Value: otelString(entry.Error.Error())},
+			otelKeyValue{Key: "exception.type", Value: otelString(fmt.Sprintf("%T", entry.Error))},
+		)
+	}
+
+	if entry.Caller != nil {
+		record.Attributes = append(record.Attributes,
+			otelKeyValue{Key: "code.filepath", Value: otelString(entry.Caller.File)},
+			otelKeyValue{Key: "code.lineno", Value: otelInt(int64(entry.Caller.Line))},
+			otelKeyValue{Key: "code.function", Value: otelString(entry.Caller.Function)},
+		)
+	}
+
+	b, err := json.Marshal(record)
+	if err != nil {
+		// otelValue only produces marshalable values, so this is not expected to happen.
+		record.Attributes = nil
+		record.Body = otelString("[LOG ERROR: failed to marshal entry]")
+		if errorBytes, innerErr := json.Marshal(record); innerErr == nil {
+			return string(errorBytes)
+		}
+		return `{"severityNumber":17,"body":{"stringValue":"[LOG ERROR: catastrophic marshal failure]"}}`
+	}
+
+	return string(b)
+}
+
+func otelString(s string) otelAnyValue {
+	return otelAnyValue{StringValue: &s}
+}
+
+func otelInt(i int64) otelAnyValue {
+	s := strconv.FormatInt(i, 10)
+	return otelAnyValue{IntValue: &s}
+}
+
+// otelValue converts a field value to an AnyValue. Values without a direct mapping are
+// converted through their JSON representation, and fall back to their fmt representation.
+func otelValue(value any, depth int) otelAnyValue {
+	if depth >= otelMaxDepth {
+		return otelString(fmt.Sprint(value))
+	}
+	if depth == 0 {
+		value, _ = resolveLogValue(value, 0)
+	}
+
+	switch v := value.(type) {
+	case *FieldError:
+		return otelString(v.String())
+	case nil:
+		return otelAnyValue{}
+	case string:
+		return otelString(v)
+	case bool:
+		return otelAnyValue{BoolValue: &v}
+	case int:
+		return otelInt(int64(v))
+	case int8:
+		return otelInt(int64(v))
+	case int16:
+		return otelInt(int64(v))
+	case int32:
+		return otelInt(int64(v))
+	case int64:
+		return otelInt(v)
+	case uint8:
+		return otelInt(int64(v))
+	case uint16:
+		return otelInt(int64(v))
+	case uint32:
+		return otelInt(int64(v))
+	case float32:
+		return otelDouble(float64(v))
+	case float64:
+		return otelDouble(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return otelInt(i)
+		}
+		if f, err := v.Float64(); err == nil {
+			return otelDouble(f)
+		}
+		return otelString(v.String())
+	case time.Time:
+		return otelString(v.Format(time.RFC3339Nano))
+	case time.Duration:
+		return otelString(v.String())
+	case error:
+		return otelString(v.Error())
+	case map[string]any:
+		kvs := make([]otelKeyValue, 0, len(v))
+		for key, item := range v {
+			kvs = append(kvs, otelKeyValue{Key: key, Value: otelValue(item, depth+1)})
+		}
+		sort.Slice(kvs, func(i, j int) bool { return kvs[i].Key < kvs[j].Key })
+		return otelAnyValue{KvlistValue: &otelKeyValues{Values: kvs}}
+	case []any:
+		values := make([]otelAnyValue, 0, len(v))
+		for _, item := range v {
+			values = append(values, otelValue(item, depth+1))
+		}
+		return otelAnyValue{ArrayValue: &otelArray{Values: values}}
+	}
+
+	// Structs, typed maps and slices: normalize through JSON, then convert the generic form.
+	if rv := reflect.ValueOf(value); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return otelAnyValue{}
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return otelString(fmt.Sprintf("%+v", value))
+	}
+	var generic any
+	decoder := json.NewDecoder(bytes.NewReader(b))
+	decoder.UseNumber()
+	if err := decoder.Decode(&generic); err != nil {
+		return otelString(string(b))
+	}
+	return otelValue(generic, depth+1)
+}
+
+// otelDouble converts a float, encoding values JSON cannot represent as strings.
+func otelDouble(f float64) otelAnyValue {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return otelString(strconv.FormatFloat(f, 'g', -1, 64))
+	}
+	return otelAnyValue{DoubleValue: &f}
+}
@@ -0,0 +1,90 @@
+package logos
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOTelFormatter_Format(t *testing.T) {
+	when := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	line := OTelFormatter().Format(LevelWarn, Entry{
+		Msg:  "disk almost full",
+		Time: when,
+		Fields: Fields{
+			"disk":            "/dev/sda1",
+			"percent":         93,
+			"ratio":           0.93,
+			"ok":              false,
+			"tags":            []string{"a", "b"},
+			FieldTraceID:      "4bf92f3577b34da6a3ce929d0e0e4736",
+			FieldSpanID:       "00f067aa0ba902b7",
+			FieldTraceSampled: true,
+		},
+		Error: assert.AnError,
+	})
+
+	var m map[string]any
+	assert.NoError(t, json.Unmarshal([]byte(line), &m))
+	assert.Equal(t, "1704164645000000006", m["timeUnixNano"])
+	assert.EqualValues(t, OTelSeverityWarn, m["severityNumber"])
+	assert.Equal(t, "warn", m["severityText"])
+	assert.Equal(t, map[string]any{"stringValue": "disk almost full"}, m["body"])
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", m["traceId"])
+	assert.Equal(t, "00f067aa0ba902b7", m["spanId"])
+	assert.EqualValues(t, 1, m["flags"])
+
+	attrs := map[string]any{}
+	for _, kv := range m["attributes"].([]any) {
+		kv := kv.(map[string]any)
+		attrs[kv["key"].(string)] = kv["value"]
+	}
+	assert.Equal(t, map[string]any{"stringValue": "/dev/sda1"}, attrs["disk"])
+	assert.Equal(t, map[string]any{"intValue": "93"}, attrs["percent"])
+	assert.Equal(t, map[string]any{"doubleValue": 0.93}, attrs["ratio"])
+	assert.Equal(t, map[string]any{"boolValue": false}, attrs["ok"])
+	assert.Equal(t, map[string]any{"arrayValue": map[string]any{"values": []any{
+		map[string]any{"stringValue": "a"},
+		map[string]any{"stringValue": "b"},
+	}}}, attrs["tags"])
+	assert.Equal(t, map[string]any{"stringValue": assert.AnError.Error()}, attrs["exception.message"])
+	assert.NotContains(t, attrs, FieldTraceID)
+	assert.NotContains(t, attrs, FieldTraceSampled)
+}
+
+func TestOTelFormatter_Severity(t *testing.T) {
+	assert.Equal(t, OTelSeverityDebug, levelRegistry.OTelSeverity(LevelDebug))
+	assert.Equal(t, OTelSeverityInfo, levelRegistry.OTelSeverity(LevelInfo))
+	assert.Equal(t, OTelSeverityError, levelRegistry.OTelSeverity(LevelError))
+	assert.Equal(t, OTelSeverityFatal, levelRegistry.OTelSeverity(LevelFatal))
+	assert.Equal(t, OTelSeverityUnspecified, levelRegistry.OTelSeverity(LevelPrint))
+
+	assert.Equal(t, OTelSeverityTrace, levelRegistry.OTelSeverity(LevelTrace))
+	assert.Equal(t, 10, levelRegistry.OTelSeverity(LevelNotice))
+	assert.Equal(t, 22, levelRegistry.OTelSeverity(LevelPanic))
+
+	// Custom levels map into the range of the nearest built-in level below them.
+	assert.Equal(t, OTelSeverityTrace, levelRegistry.OTelSeverity(LevelTrace-10))
+	assert.Equal(t, 24, levelRegistry.OTelSeverity(Level(100)))
+}
+
+func TestOTelValue_Structs(t *testing.T) {
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	v := otelValue(user{ID: 7, Name: "alice"}, 0)
+	if assert.NotNil(t, v.KvlistValue) {
+		assert.Equal(t, []otelKeyValue{
+			{Key: "id", Value: otelInt(7)},
+			{Key: "name", Value: otelString("alice")},
+		}, v.KvlistValue.Values)
+	}
+
+	// Values JSON cannot encode fall back to their fmt representation.
+	v = otelValue(make(chan int), 0)
+	assert.NotNil(t, v.StringValue)
+}
@@ -0,0 +1,274 @@
+package logos
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// patternFormatter renders log entries from a layout string of literal text and placeholders.
+type patternFormatter struct {
+	cfg   Config
+	parts []patternPart
+}
+
+// patternPart is either literal text or a placeholder with its modifiers.
+type patternPart struct {
+	literal string
+	render  func(f *patternFormatter, level Level, entry Entry) string
+	mods    patternMods
+}
+
+// patternMods holds the padding, truncation and case modifiers of a placeholder.
+type patternMods struct {
+	width     int
+	leftAlign bool
+	maxLen    int // Zero means no truncation
+	upper     bool
+	lower     bool
+}
+
+// patternWidth matches width modifiers such as "-5", "10", ".8" and "-10.10".
+var patternWidth = regexp.MustCompile(`^(-?)(\d*)(?:\.(\d+))?$`)
+
+// NewPatternFormatter creates a formatter from a layout made of literal text and placeholders:
+//
+//	%{time}           entry time, using cfg.Timestamp if set, RFC 3339 otherwise
+//	%{time:LAYOUT}    entry time in a Go time layout, e.g. %{time:15:04:05.000}
+//	%{level}          level name
+//	%{logger}         logger name (see Logger.Named)
+//	%{msg}            message
+//	%{error}          error message, empty if none
+//	%{fields}         all fields as sorted key=value pairs
+//	%{field:KEY}      the value of a single field, empty if absent; "a.b" selects b in group a
+//	%{caller}         caller as file.go:line (requires Logger.WithCaller)
+//	%{file}, %{line}, %{func}
+//	%%                a literal percent sign
+//
+// Every placeholder except time accepts modifiers after a colon: a width such as "-5"
+// (left-aligned) or "5" (right-aligned), a truncation such as ".20", and "upper" or "lower".
+// Modifiers can be combined, e.g. %{level:upper:-5} or %{field:user:-10.10}.
+func NewPatternFormatter(layout string, cfg Config) (Formatter, error) {
+	f := &patternFormatter{cfg: cfg}
+
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			f.parts = append(f.parts, patternPart{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(layout); i++ {
+		c := layout[i]
+		if c != '%' {
+			literal.WriteByte(c)
+			continue
+		}
+		if i+1 < len(layout) && layout[i+1] == '%' {
+			literal.WriteByte('%')
+			i++
+			continue
+		}
+		if i+1 >= len(layout) || layout[i+1] != '{' {
+			return nil, fmt.Errorf("pattern: expected '{' after '%%' at offset %d", i)
+		}
+		end := strings.IndexByte(layout[i+2:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("pattern: unterminated placeholder at offset %d", i)
+		}
+
+		part, err := parsePlaceholder(layout[i+2 : i+2+end])
+		if err != nil {
+			return nil, err
+		}
+		flush()
+		f.parts = append(f.parts, part)
+		i += end + 2
+	}
+	flush()
+
+	return f, nil
+}
+
+// parsePlaceholder parses the text between "%{" and "}".
+func parsePlaceholder(spec string) (patternPart, error) {
+	name, args, _ := strings.Cut(spec, ":")
+
+	// The time layout may itself contain colons, so it takes the rest of the placeholder.
+	if name == "time" {
+		layout := args
+		return patternPart{render: func(f *patternFormatter, level Level, entry Entry) string {
+			return f.time(entry, layout)
+		}}, nil
+	}
+
+	var mods []string
+	if args != "" {
+		mods = strings.Split(args, ":")
+	}
+
+	var render func(f *patternFormatter, level Level, entry Entry) string
+	switch name {
+	case "level":
+		render = func(f *patternFormatter, level Level, entry Entry) string {
+			return GetLevelName(level, &f.cfg)
+		}
+	case "logger":
+		render = func(f *patternFormatter, level Level, entry Entry) string {
+			return entry.Name
+		}
+	case "msg", "message":
+		render = func(f *patternFormatter, level Level, entry Entry) string {
+			return entry.Msg
+		}
+	case "error":
+		render = func(f *patternFormatter, level Level, entry Entry) string {
+			if entry.Error == nil {
+				return ""
+			}
+			return entry.Error.Error()
+		}
+	case "fields":
+		render = func(f *patternFormatter, level Level, entry Entry) string {
+			return f.fields(entry)
+		}
+	case "field":
+		if len(mods) == 0 || mods[0] == "" {
+			return patternPart{}, fmt.Errorf("pattern: %%{field} requires a key, e.g. %%{field:user_id}")
+		}
+		key := mods[0]
+		mods = mods[1:]
+		render = func(f *patternFormatter, level Level, entry Entry) string {
+			value, ok := lookupField(entry.Fields, key)
+			if !ok {
+				return ""
+			}
+			if s, ok := value.(string); ok {
+				return s
+			}
+			return encodeFieldString(value, &f.cfg)
+		}
+	case "caller":
+		render = func(f *patternFormatter, level Level, entry Entry) string {
+			if entry.Caller == nil {
+				return ""
+			}
+			return entry.Caller.String()
+		}
+	case "file":
+		render = func(f *patternFormatter, level Level, entry Entry) string {
+			if entry.Caller == nil {
+				return ""
+			}
+			return filepath.Base(entry.Caller.File)
+		}
+	case "line":
+		render = func(f *patternFormatter, level Level, entry Entry) string {
+			if entry.Caller == nil {
+				return ""
+			}
+			return strconv.Itoa(entry.Caller.Line)
+		}
+	case "func":
+		render = func(f *patternFormatter, level Level, entry Entry) string {
+			if entry.Caller == nil {
+				return ""
+			}
+			return entry.Caller.ShortFunction()
+		}
+	default:
+		return patternPart{}, fmt.Errorf("pattern: unknown placeholder %%{%s}", name)
+	}
+
+	part := patternPart{render: render}
+	for _, mod := range mods {
+		switch {
+		case mod == "upper":
+			part.mods.upper = true
+		case mod == "lower":
+			part.mods.lower = true
+		case patternWidth.MatchString(mod) && mod != "" && mod != "-":
+			m := patternWidth.FindStringSubmatch(mod)
+			part.mods.leftAlign = m[1] == "-"
+			if m[2] != "" {
+				part.mods.width, _ = strconv.Atoi(m[2])
+			}
+			if m[3] != "" {
+				part.mods.maxLen, _ = strconv.Atoi(m[3])
+			}
+		default:
+			return patternPart{}, fmt.Errorf("pattern: unknown modifier %q in %%{%s}", mod, spec)
+		}
+	}
+	return part, nil
+}
+
+// Format renders the log entry according to the layout.
+func (f *patternFormatter) Format(level Level, entry Entry) string {
+	var sb strings.Builder
+	for _, part := range f.parts {
+		if part.render == nil {
+			sb.WriteString(part.literal)
+			continue
+		}
+		sb.WriteString(part.mods.apply(part.render(f, level, entry)))
+	}
+	return sb.String()
+}
+
+// time formats the entry time with the given layout.
+func (f *patternFormatter) time(entry Entry, layout string) string {
+	if layout == "" && f.cfg.Timestamp != nil {
+		return f.cfg.Timestamp()
+	}
+
+	t := entry.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	return t.Format(layout)
+}
+
+// fields renders the error and fields as sorted key=value pairs, as the text formatter does.
+func (f *patternFormatter) fields(entry Entry) string {
+	var tuples []string
+	if entry.Error != nil {
+		tuples = append(tuples, fmt.Sprintf("error=%q", entry.Error.Error()))
+	}
+	forEachField(entry.Fields, func(key string, value any) {
+		tuples = append(tuples, fmt.Sprintf("%s=%s", key, encodeFieldString(value, &f.cfg)))
+	})
+	sort.Strings(tuples)
+	return strings.Join(tuples, " ")
+}
+
+// apply transforms a rendered placeholder value: case first, then truncation, then padding.
+func (m patternMods) apply(s string) string {
+	if m.upper {
+		s = strings.ToUpper(s)
+	} else if m.lower {
+		s = strings.ToLower(s)
+	}
+
+	if m.maxLen > 0 && utf8.RuneCountInString(s) > m.maxLen {
+		s = string([]rune(s)[:m.maxLen])
+	}
+
+	if pad := m.width - utf8.RuneCountInString(s); pad > 0 {
+		if m.leftAlign {
+			s += strings.Repeat(" ", pad)
+		} else {
+			s = strings.Repeat(" ", pad) + s
+		}
+	}
+	return s
+}
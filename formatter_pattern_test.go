@@ -0,0 +1,113 @@
+package logos
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPatternFormatter_Format(t *testing.T) {
+	fmtr, err := NewPatternFormatter("%{time:15:04:05.000} %{level:upper:-5} [%{logger}] %{msg} %{fields}", DefaultConfig)
+	assert.NoError(t, err)
+
+	line := fmtr.Format(LevelInfo, Entry{
+		Msg:    "request served",
+		Time:   time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC),
+		Name:   "http.server",
+		Fields: Fields{"status": 200, "path": "/users"},
+		Error:  assert.AnError,
+	})
+	assert.Equal(t, `03:04:05.678 INFO  [http.server] request served error="`+assert.AnError.Error()+`" path="/users" status=200`, line)
+}
+
+func TestPatternFormatter_Modifiers(t *testing.T) {
+	tests := []struct {
+		layout string
+		want   string
+	}{
+		{"%{level}", "warn"},
+		{"%{level:upper}", "WARN"},
+		{"%{level:-6}|", "warn  |"},
+		{"%{level:6}|", "  warn|"},
+		{"%{msg:.5}", "Hello"},
+		{"%{msg:-8.3}|", "Hel     |"},
+		{"%{msg:upper:.3}", "HEL"},
+		{"%{field:user}", "alice"},
+		{"%{field:count}", "3"},
+		{"%{field:missing:3}|", "   |"},
+		{"100%% %{msg:lower}", "100% hello world"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			fmtr, err := NewPatternFormatter(tt.layout, DefaultConfig)
+			if assert.NoError(t, err) {
+				line := fmtr.Format(LevelWarn, Entry{Msg: "Hello World", Fields: Fields{"user": "alice", "count": 3}})
+				assert.Equal(t, tt.want, line)
+			}
+		})
+	}
+}
+
+func TestPatternFormatter_Caller(t *testing.T) {
+	buf := &bytes.Buffer{}
+	fmtr, err := NewPatternFormatter("%{file}|%{func}|%{caller}", DefaultConfig)
+	assert.NoError(t, err)
+
+	NewLogger(LevelInfo, fmtr, buf).WithCaller(true).Info("test")
+	parts := strings.Split(strings.TrimSpace(buf.String()), "|")
+	if assert.Len(t, parts, 3) {
+		assert.Equal(t, "formatter_pattern_test.go", parts[0])
+		assert.Equal(t, "TestPatternFormatter_Caller", parts[1])
+		assert.Regexp(t, `^formatter_pattern_test\.go:\d+$`, parts[2])
+	}
+
+	// Without caller capture, caller placeholders are empty.
+	buf.Reset()
+	NewLogger(LevelInfo, fmtr, buf).Info("test")
+	assert.Equal(t, "||\n", buf.String())
+}
+
+func TestPatternFormatter_Time(t *testing.T) {
+	fmtr, err := NewPatternFormatter("%{time}", Config{Timestamp: func() string { return "static" }})
+	assert.NoError(t, err)
+	assert.Equal(t, "static", fmtr.Format(LevelInfo, Entry{}))
+
+	fmtr, err = NewPatternFormatter("%{time}", Config{})
+	assert.NoError(t, err)
+	when := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	assert.Equal(t, "2024-01-02T03:04:05Z", fmtr.Format(LevelInfo, Entry{Time: when}))
+}
+
+func TestPatternFormatter_Errors(t *testing.T) {
+	for _, layout := range []string{
+		"%{unknown}",
+		"%{msg",
+		"%msg",
+		"%{field}",
+		"%{level:sideways}",
+		"trailing %",
+	} {
+		_, err := NewPatternFormatter(layout, DefaultConfig)
+		assert.Error(t, err, layout)
+	}
+}
+
+func TestLogger_Named(t *testing.T) {
+	buf := &bytes.Buffer{}
+	fmtr, err := NewPatternFormatter("[%{logger}] %{msg}", DefaultConfig)
+	assert.NoError(t, err)
+
+	log := NewLogger(LevelInfo, fmtr, buf)
+	log.Info("root")
+	assert.Equal(t, "[] root\n", buf.String())
+	buf.Reset()
+
+	db := log.Named("app").Named("db")
+	assert.Equal(t, "app.db", db.GetName())
+	db.Info("query")
+	assert.Equal(t, "[app.db] query\n", buf.String())
+	assert.Equal(t, "", log.GetName())
+}
@@ -0,0 +1,106 @@
+package logos
+
+import (
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TemplateEntry is the data passed to templates executed by a template formatter.
+type TemplateEntry struct {
+	Time      time.Time
+	Timestamp string // Result of Config.Timestamp, or Time in RFC 3339 if unset
+	Level     Level
+	LevelName string
+	Logger    string
+	Msg       string
+	Error     error
+	Fields    Fields
+	Caller    *Caller
+}
+
+// templateFormatter renders log entries by executing a text/template.
+type templateFormatter struct {
+	cfg  Config
+	tmpl *template.Template
+}
+
+// NewTemplateFormatter creates a formatter that executes a text/template for each entry,
+// with a TemplateEntry as data. Besides the standard template functions, it provides:
+//
+//	upper, lower        change case
+//	pad N s             pad s to N characters (negative N aligns left)
+//	trunc N s           truncate s to N characters
+//	field "key" .Fields render a single field value
+//	fields .            render the error and fields as sorted key=value pairs
+//
+// For example: {{.Time.Format "15:04:05"}} {{.LevelName | upper | pad -5}} {{.Msg}} {{fields .}}
+func NewTemplateFormatter(text string, cfg Config) (Formatter, error) {
+	f := &templateFormatter{cfg: cfg}
+
+	tmpl, err := template.New("logos").Funcs(template.FuncMap{
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"pad": func(width int, s string) string {
+			mods := patternMods{width: width}
+			if width < 0 {
+				mods = patternMods{width: -width, leftAlign: true}
+			}
+			return mods.apply(s)
+		},
+		"trunc": func(n int, s string) string {
+			return patternMods{maxLen: n}.apply(s)
+		},
+		"field": func(key string, fields Fields) string {
+			value, ok := lookupField(fields, key)
+			if !ok {
+				return ""
+			}
+			if s, ok := value.(string); ok {
+				return s
+			}
+			return encodeFieldString(value, &f.cfg)
+		},
+		"fields": func(e TemplateEntry) string {
+			pattern := patternFormatter{cfg: f.cfg}
+			return pattern.fields(Entry{Fields: e.Fields, Error: e.Error})
+		},
+	}).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	f.tmpl = tmpl
+
+	return f, nil
+}
+
+// Format renders the log entry by executing the template. If execution fails,
+// the error is reported in the output together with the message.
+func (f *templateFormatter) Format(level Level, entry Entry) string {
+	t := entry.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	timestamp := t.Format(time.RFC3339)
+	if f.cfg.Timestamp != nil {
+		timestamp = f.cfg.Timestamp()
+	}
+
+	data := TemplateEntry{
+		Time:      t,
+		Timestamp: timestamp,
+		Level:     level,
+		LevelName: GetLevelName(level, &f.cfg),
+		Logger:    entry.Name,
+		Msg:       entry.Msg,
+		Error:     entry.Error,
+		Fields:    entry.Fields,
+		Caller:    entry.Caller,
+	}
+
+	var sb strings.Builder
+	if err := f.tmpl.Execute(&sb, data); err != nil {
+		return "[LOG ERROR: " + err.Error() + "] " + entry.Msg
+	}
+	return sb.String()
+}
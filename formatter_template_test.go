@@ -0,0 +1,35 @@
+package logos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateFormatter_Format(t *testing.T) {
+	fmtr, err := NewTemplateFormatter(
+		`{{.Time.Format "15:04:05"}} {{.LevelName | upper | pad -5}} [{{.Logger | trunc 4}}] {{.Msg}} user={{field "user" .Fields}} {{fields .}}`,
+		DefaultConfig)
+	assert.NoError(t, err)
+
+	line := fmtr.Format(LevelWarn, Entry{
+		Msg:    "slow query",
+		Time:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		Name:   "database",
+		Fields: Fields{"user": "alice", "ms": 1200},
+	})
+	assert.Equal(t, `03:04:05 WARN  [data] slow query user=alice ms=1200 user="alice"`, line)
+}
+
+func TestTemplateFormatter_Errors(t *testing.T) {
+	_, err := NewTemplateFormatter("{{.Msg", DefaultConfig)
+	assert.Error(t, err)
+
+	// Execution errors are reported in the output instead of losing the message.
+	fmtr, err := NewTemplateFormatter("{{.Missing}}", DefaultConfig)
+	assert.NoError(t, err)
+	line := fmtr.Format(LevelInfo, Entry{Msg: "still here"})
+	assert.Contains(t, line, "[LOG ERROR:")
+	assert.Contains(t, line, "still here")
+}
@@ -1,7 +1,6 @@
 package logos
 
 import (
-	"encoding/json"
 	"fmt"
 	"slices"
 	"strings"
@@ -26,15 +25,10 @@ func (f textFormatter) Format(level Level, entry Entry) string {
 		tuples = append(tuples, fmt.Sprintf("error=%q", string(errMsg)))
 	}
 
-	for key, value := range entry.Fields {
-		b, err := json.Marshal(value)
-		if err != nil {
-			// If marshal fails, include an error indicator instead of silently failing
-			tuples = append(tuples, fmt.Sprintf("%s=<marshal_error>", key))
-		} else {
-			tuples = append(tuples, fmt.Sprintf("%s=%v", key, string(b)))
-		}
-	}
+	forEachField(entry.Fields, func(key string, value any) {
+		// Values that cannot be encoded are replaced by a marker instead of being dropped
+		tuples = append(tuples, fmt.Sprintf("%s=%s", key, encodeFieldString(value, &f.cfg)))
+	})
 	slices.Sort(tuples)
 
 	// If there are tuples, add a tab to separate them from the message
@@ -143,7 +143,7 @@ func TestTextFormatter_MarshalError(t *testing.T) {
 	log.With("circular", circ).Info("test message")
 
 	output := buf.String()
-	assert.Contains(t, output, "circular=<marshal_error>", "Should indicate marshal error")
+	assert.Contains(t, output, "circular=<marshal_error type=*logos.circular: cycle detected", "Should indicate marshal error")
 	assert.Contains(t, output, "test message", "Should still include the message")
 }
 
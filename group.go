@@ -0,0 +1,115 @@
+package logos
+
+import (
+	"sort"
+	"strings"
+)
+
+// Group is a set of fields nested under a single key. The JSON-based formatters write it
+// as a nested object, and the text, console and pattern formatters flatten it into dotted
+// keys such as http.method=GET. Empty groups are left out of the output.
+type Group map[string]any
+
+// WithGroup returns a new Logger that nests the fields added after it under name.
+// Groups compose across derived loggers:
+//
+//	logger.WithGroup("http").WithGroup("req").With("method", "GET") // http.req.method=GET
+//
+// Fields added before WithGroup stay where they are. A group with no fields is not written,
+// and an empty name returns the logger unchanged.
+func (logger Logger) WithGroup(name string) Logger {
+	if name == "" {
+		return logger
+	}
+	newLogger := logger.Copy()
+	newLogger.groups = append(logger.groups[:len(logger.groups):len(logger.groups)], name)
+	return newLogger
+}
+
+// FieldBadKey holds a field value that was in the way of a group of the same name. The value
+// is moved into the group under this key, e.g. http.!BADKEY=1, rather than being lost.
+const FieldBadKey = "!BADKEY"
+
+// groupFields returns the group of fields at path, creating it or copying it as needed so
+// that groups shared with other loggers are never modified. A non-group value in the way
+// is kept in the group under FieldBadKey.
+func groupFields(fields Fields, path []string) Group {
+	current := fields
+	for _, name := range path {
+		next := Group{}
+		switch existing := current[name].(type) {
+		case Group:
+			for k, v := range existing {
+				next[k] = v
+			}
+		case nil:
+			if value, ok := current[name]; ok {
+				next[FieldBadKey] = value
+			}
+		default:
+			next[FieldBadKey] = existing
+		}
+		current[name] = next
+		current = next
+	}
+	return current
+}
+
+// isEmptyGroup reports whether value is a group containing nothing but empty groups.
+func isEmptyGroup(value any) bool {
+	group, ok := value.(Group)
+	if !ok {
+		return false
+	}
+	for _, v := range group {
+		if !isEmptyGroup(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// forEachField calls visit for each field in key order, flattening groups into dotted keys
+// and skipping empty groups.
+func forEachField(fields Fields, visit func(key string, value any)) {
+	forEachGroupField("", fields, visit)
+}
+
+func forEachGroupField(prefix string, fields map[string]any, visit func(key string, value any)) {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := fields[key]
+		if group, ok := value.(Group); ok {
+			forEachGroupField(prefix+key+".", group, visit)
+			continue
+		}
+		visit(prefix+key, value)
+	}
+}
+
+// lookupField returns the field stored under key, where a dotted key also selects a field
+// inside groups, e.g. "http.method".
+func lookupField(fields Fields, key string) (any, bool) {
+	if value, ok := fields[key]; ok {
+		return value, true
+	}
+
+	current := map[string]any(fields)
+	for {
+		name, rest, found := strings.Cut(key, ".")
+		if !found {
+			value, ok := current[name]
+			return value, ok
+		}
+		group, ok := current[name].(Group)
+		if !ok {
+			return nil, false
+		}
+		current, key = group, rest
+	}
+}
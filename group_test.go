@@ -0,0 +1,106 @@
+package logos
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_WithGroup_JSON(t *testing.T) {
+	buf := &bytes.Buffer{}
+	base := NewLogger(LevelInfo, JSONFormatter(), buf).With("id", "app-1")
+	http := base.WithGroup("http").With("method", "GET").With("status", 200)
+	req := http.WithGroup("req").With("id", "req-9")
+
+	req.Info("hello")
+	m := Map(buf)
+	assert.Equal(t, "app-1", m.Field("id"))
+	assert.Equal(t, map[string]any{
+		"method": "GET",
+		"status": float64(200),
+		"req":    map[string]any{"id": "req-9"},
+	}, m.Field("http"))
+
+	// Derived loggers do not affect their parents.
+	http.Info("parent")
+	assert.Equal(t, map[string]any{"method": "GET", "status": float64(200)}, Map(buf).Field("http"))
+	base.Info("base")
+	assert.Nil(t, Map(buf).Field("http"))
+}
+
+func TestLogger_WithGroup_Text(t *testing.T) {
+	for _, formatter := range []Formatter{TextFormatter(), ConsoleFormatter()} {
+		buf := &bytes.Buffer{}
+		NewLogger(LevelInfo, formatter, buf).
+			With("status", "ok").
+			WithGroup("http").WithFields(Fields{"method": "GET", "status": 200}).
+			WithGroup("req").With("id", 7).
+			Info("hello")
+
+		out := buf.String()
+		assert.Contains(t, out, `http.method="GET" http.req.id=7 http.status=200 status="ok"`)
+	}
+}
+
+func TestLogger_WithGroup_Empty(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := NewLogger(LevelInfo, JSONFormatter(), buf)
+
+	log.WithGroup("http").WithGroup("req").Info("hello")
+	m := Map(buf)
+	assert.Nil(t, m["fields"])
+
+	log.With("a", 1).WithGroup("http").Info("hello")
+	m = Map(buf)
+	assert.Equal(t, map[string]any{"a": float64(1)}, m["fields"])
+
+	log.With("empty", Group{"nested": Group{}}).With("b", 2).Info("hello")
+	assert.Equal(t, map[string]any{"b": float64(2)}, Map(buf)["fields"])
+
+	buf.Reset()
+	NewLogger(LevelInfo, TextFormatter(), buf).With("empty", Group{}).Info("hello")
+	assert.NotContains(t, buf.String(), "empty")
+
+	assert.Equal(t, log.GetFields(), log.WithGroup("").GetFields())
+}
+
+func TestLogger_WithGroup_Pattern(t *testing.T) {
+	f, err := NewPatternFormatter("%{field:http.method} %{fields}", Config{})
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	NewLogger(LevelInfo, f, buf).WithGroup("http").With("method", "GET").Info("hello")
+	assert.Equal(t, `GET http.method="GET"`, strings.TrimSpace(buf.String()))
+}
+
+func TestLogger_WithGroup_Redaction(t *testing.T) {
+	buf := &bytes.Buffer{}
+	NewLogger(LevelInfo, TextFormatter(), buf).
+		WithRedactor(NewRedactor(RedactorOptions{})).
+		WithGroup("db").With("password", "hunter2").With("user", "app").
+		Info("connect")
+
+	out := buf.String()
+	assert.Contains(t, out, `db.password="[REDACTED]"`)
+	assert.Contains(t, out, `db.user="app"`)
+}
+
+func TestLogger_WithGroup_Conflict(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := NewLogger(LevelInfo, JSONFormatter(), buf).With("http", "v1")
+	grouped := log.WithGroup("http").With("method", "GET")
+
+	grouped.Info("hello")
+	assert.Equal(t, map[string]any{FieldBadKey: "v1", "method": "GET"}, Map(buf).Field("http"))
+
+	// The logger the group was derived from keeps its plain value.
+	log.Info("parent")
+	assert.Equal(t, "v1", Map(buf).Field("http"))
+
+	buf.Reset()
+	NewLogger(LevelInfo, TextFormatter(), buf).With("http", 1).
+		WithGroup("http").With("method", "GET").Info("hello")
+	assert.Contains(t, buf.String(), `http.!BADKEY=1 http.method="GET"`)
+}
@@ -0,0 +1,22 @@
+package logos
+
+import (
+	"time"
+)
+
+// HTTPRequest describes a served or outbound HTTP request for access-log entries.
+// Attach it to an entry under the FieldHTTPRequest key. Formatters with a dedicated
+// slot for request data, such as GCPFormatter, lift it out of the regular fields.
+type HTTPRequest struct {
+	Method       string        `json:"method,omitempty"`
+	URL          string        `json:"url,omitempty"`
+	Status       int           `json:"status,omitempty"`
+	RequestSize  int64         `json:"request_size,omitempty"`
+	ResponseSize int64         `json:"response_size,omitempty"`
+	UserAgent    string        `json:"user_agent,omitempty"`
+	RemoteIP     string        `json:"remote_ip,omitempty"`
+	ServerIP     string        `json:"server_ip,omitempty"`
+	Referer      string        `json:"referer,omitempty"`
+	Protocol     string        `json:"protocol,omitempty"`
+	Latency      time.Duration `json:"latency,omitempty"`
+}
@@ -0,0 +1,54 @@
+package logos
+
+import (
+	"encoding/json"
+)
+
+// LazyValue is a field value computed only when an entry carrying it is emitted.
+// It is evaluated once per emitted entry, and never when the entry's level is disabled
+// on every destination.
+type LazyValue func() any
+
+// LogValue calls the function.
+func (f LazyValue) LogValue() any {
+	return f()
+}
+
+// Lazy returns a field value that calls f only when the entry is emitted:
+//
+//	log.With("payload", logos.Lazy(func() any { return buildDebugPayload() })).Debug("request")
+func Lazy(f func() any) LazyValue {
+	return LazyValue(f)
+}
+
+// LazyOf is Lazy for functions returning a specific type.
+func LazyOf[T any](f func() T) LazyValue {
+	return func() any { return f() }
+}
+
+// LazyString is Lazy for functions returning a string.
+func LazyString(f func() string) LazyValue {
+	return LazyOf(f)
+}
+
+// LazyInt is Lazy for functions returning an int.
+func LazyInt(f func() int) LazyValue {
+	return LazyOf(f)
+}
+
+// LazyJSON returns a field value embedding the JSON document produced by f when the
+// entry is emitted. If f fails or returns invalid JSON, the field is replaced by a
+// FieldError marker.
+func LazyJSON(f func() ([]byte, error)) LogMarshaler {
+	return lazyJSON(f)
+}
+
+type lazyJSON func() ([]byte, error)
+
+func (f lazyJSON) MarshalLog() (any, error) {
+	b, err := f()
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(b), nil
+}
@@ -0,0 +1,64 @@
+package logos
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazy_SkippedWhenNoDestinationEnabled(t *testing.T) {
+	buf1 := &bytes.Buffer{}
+	buf2 := &bytes.Buffer{}
+	mainCalls, teeCalls := 0, 0
+
+	tee := NewLogger(LevelDebug, JSONFormatter(), buf2).
+		With("payload", Lazy(func() any { teeCalls++; return "tee" }))
+	log := NewLogger(LevelInfo, JSONFormatter(), buf1).
+		With("payload", Lazy(func() any { mainCalls++; return "main" })).
+		Tee(tee)
+
+	log.Log(LevelDebug-1, "nobody")
+	assert.Equal(t, 0, mainCalls)
+	assert.Equal(t, 0, teeCalls)
+
+	log.Debug("tee only")
+	assert.Equal(t, 0, mainCalls)
+	assert.Equal(t, 1, teeCalls)
+	assert.Equal(t, "tee", Map(buf2).Field("payload"))
+
+	log.Info("both")
+	assert.Equal(t, 1, mainCalls)
+	assert.Equal(t, 2, teeCalls)
+	assert.Equal(t, "main", Map(buf1).Field("payload"))
+}
+
+func TestLazy_EvaluatedOncePerEntry(t *testing.T) {
+	for format := range FormatNames {
+		calls := 0
+		buf := &bytes.Buffer{}
+		log := NewLogger(LevelInfo, NewFormatter(format), buf).
+			With("n", LazyInt(func() int { calls++; return calls }))
+
+		log.Info("first")
+		log.Info("second")
+		assert.Equal(t, 2, calls, FormatNames[format])
+	}
+}
+
+func TestLazy_Variants(t *testing.T) {
+	buf := &bytes.Buffer{}
+	NewLogger(LevelInfo, JSONFormatter(), buf).WithFields(Fields{
+		"string": LazyString(func() string { return "s" }),
+		"typed":  LazyOf(func() []int { return []int{1, 2} }),
+		"json":   LazyJSON(func() ([]byte, error) { return []byte(`{"a":1}`), nil }),
+		"failed": LazyJSON(func() ([]byte, error) { return nil, fmt.Errorf("no payload") }),
+	}).Info("hello")
+
+	m := Map(buf)
+	assert.Equal(t, "s", m.Field("string"))
+	assert.Equal(t, []any{float64(1), float64(2)}, m.Field("typed"))
+	assert.Equal(t, map[string]any{"a": float64(1)}, m.Field("json"))
+	assert.Equal(t, "no payload", m.Field("failed").(map[string]any)["marshal_error"])
+}
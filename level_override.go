@@ -0,0 +1,39 @@
+package logos
+
+import (
+	"context"
+)
+
+// CtxKeyLevelOverride is the key used to store a level override in context.Context.
+const CtxKeyLevelOverride contextKey = "logos.level_override"
+
+// WithLevelOverride returns a new context that makes loggers obtained with FromContext, and
+// the *Context methods of any logger, use level instead of their own, for example to log
+// a single request at LevelDebug without changing the global level.
+// The override applies to the main logger only; tee loggers keep their levels.
+func WithLevelOverride(ctx context.Context, level Level) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, CtxKeyLevelOverride, level)
+}
+
+// LevelOverride returns the level override stored in the context, if any.
+func LevelOverride(ctx context.Context) (Level, bool) {
+	if ctx == nil {
+		return 0, false
+	}
+	level, ok := ctx.Value(CtxKeyLevelOverride).(Level)
+	return level, ok
+}
+
+// withContextLevel returns the logger with the context's level override applied.
+// Only the level pointer is replaced, so this is cheap enough to call for every entry.
+func (logger Logger) withContextLevel(ctx context.Context) Logger {
+	level, ok := LevelOverride(ctx)
+	if !ok || logger.level == nil || *logger.level == level {
+		return logger
+	}
+	logger.level = &level
+	return logger
+}
@@ -0,0 +1,60 @@
+package logos
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithLevelOverride_FromContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := NewLogger(LevelInfo, JSONFormatter(), buf)
+	ctx := WithLogger(context.Background(), log)
+
+	FromContext(ctx).Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	debugCtx := WithLevelOverride(ctx, LevelDebug)
+	FromContext(debugCtx).Debug("shown")
+	assert.Equal(t, "shown", Map(buf)["msg"])
+
+	// Neither the stored logger nor other contexts are affected.
+	assert.Equal(t, LevelInfo, log.GetLevel())
+	assert.Equal(t, LevelInfo, FromContext(ctx).GetLevel())
+
+	level, ok := LevelOverride(debugCtx)
+	assert.True(t, ok)
+	assert.Equal(t, LevelDebug, level)
+	_, ok = LevelOverride(ctx)
+	assert.False(t, ok)
+}
+
+func TestWithLevelOverride_ContextMethods(t *testing.T) {
+	buf := &bytes.Buffer{}
+	teeBuf := &bytes.Buffer{}
+	log := NewLogger(LevelWarn, JSONFormatter(), buf).Tee(NewLogger(LevelError, JSONFormatter(), teeBuf))
+
+	ctx := WithLevelOverride(context.Background(), LevelDebug)
+	log.DebugContext(ctx, "debug")
+	assert.Equal(t, "debug", Map(buf)["msg"])
+	assert.Empty(t, teeBuf.String()) // Tee loggers keep their own level
+
+	log.Debug("plain")
+	assert.Empty(t, buf.String())
+
+	// Overrides can also make a logger less verbose.
+	log.WarnContext(WithLevelOverride(context.Background(), LevelError), "quiet")
+	assert.Empty(t, buf.String())
+}
+
+func TestWithLevelOverride_DefaultLogger(t *testing.T) {
+	buf := &bytes.Buffer{}
+	SetDefaultLogger(NewLogger(LevelInfo, JSONFormatter(), buf))
+
+	ctx := WithLevelOverride(context.Background(), LevelDebug)
+	DebugContext(ctx, "via default")
+	assert.Equal(t, "via default", Map(buf)["msg"])
+	assert.Equal(t, LevelInfo, getDefaultLogger().GetLevel())
+}
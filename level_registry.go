@@ -0,0 +1,293 @@
+package logos
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/goodblaster/errors"
+)
+
+// SyslogSeverity is a message severity as defined by RFC 5424.
+type SyslogSeverity int
+
+const (
+	SyslogEmergency SyslogSeverity = iota
+	SyslogAlert
+	SyslogCritical
+	SyslogError
+	SyslogWarning
+	SyslogNotice
+	SyslogInformational
+	SyslogDebug
+)
+
+// Syslog returns a pointer to severity, for setting LevelSpec.SyslogSeverity.
+func Syslog(severity SyslogSeverity) *SyslogSeverity {
+	return &severity
+}
+
+// After returns a pointer to level, for setting LevelSpec.Rank.
+func After(level Level) *Level {
+	return &level
+}
+
+// LevelSpec describes a registered level. Severities left unset (nil or zero) are taken from
+// the nearest registered level below, so a level registered just above warn is reported as a
+// warning.
+//
+// Rank places the level in the severity order right after another level, so that loggers
+// filter it there: a level ranked after LevelNotice is written by loggers at notice and
+// dropped by loggers at warn. Without it, the level ranks by value. Only the package registry
+// ranks levels, since loggers filter without a Config.
+type LevelSpec struct {
+	Name           string          // Rendered name, also accepted by ParseLevel. Required.
+	Aliases        []string        // Other names accepted by ParseLevel, such as "warning".
+	Color          Color           // Console color. Defaults to ColorReset.
+	SyslogSeverity *SyslogSeverity // Severity for syslog-based protocols, such as Cloud Logging; see Syslog.
+	OTelSeverity   int             // OpenTelemetry severity number, from 1 (TRACE) to 24 (FATAL4).
+	Rank           *Level          // Level this one ranks right after; see After.
+}
+
+// clone returns a copy of spec sharing nothing with it.
+func (spec LevelSpec) clone() LevelSpec {
+	spec.Aliases = append([]string(nil), spec.Aliases...)
+	if spec.SyslogSeverity != nil {
+		spec.SyslogSeverity = Syslog(*spec.SyslogSeverity)
+	}
+	if spec.Rank != nil {
+		spec.Rank = After(*spec.Rank)
+	}
+	return spec
+}
+
+// LevelRegistry maps levels to their names, colors and protocol severities. The package
+// registry, changed with RegisterLevel, is used unless a Config has a registry of its own,
+// which keeps tests and embedded loggers from affecting each other. A LevelRegistry is safe
+// for concurrent use.
+type LevelRegistry struct {
+	mu       sync.RWMutex
+	specs    map[Level]LevelSpec
+	names    map[string]Level       // Lower-case names and aliases
+	onChange func(r *LevelRegistry) // Called with the lock held after every change
+}
+
+// NewLevelRegistry returns a registry holding the built-in levels.
+func NewLevelRegistry() *LevelRegistry {
+	r := &LevelRegistry{specs: map[Level]LevelSpec{}, names: map[string]Level{}}
+	builtin := map[Level]LevelSpec{
+		LevelTrace:     {Name: "trace", Color: ColorTextWhite, SyslogSeverity: Syslog(SyslogDebug), OTelSeverity: OTelSeverityTrace},
+		LevelDebug:     {Name: "debug", Color: ColorTextBlue, SyslogSeverity: Syslog(SyslogDebug), OTelSeverity: OTelSeverityDebug},
+		LevelInfo:      {Name: "info", Color: ColorTextGreen, SyslogSeverity: Syslog(SyslogInformational), OTelSeverity: OTelSeverityInfo},
+		LevelNotice:    {Name: "notice", Color: ColorTextCyan, SyslogSeverity: Syslog(SyslogNotice), OTelSeverity: OTelSeverityInfo + 1},
+		LevelWarn:      {Name: "warn", Aliases: []string{"warning"}, Color: ColorTextYellow, SyslogSeverity: Syslog(SyslogWarning), OTelSeverity: OTelSeverityWarn},
+		LevelError:     {Name: "error", Aliases: []string{"err"}, Color: ColorTextRed, SyslogSeverity: Syslog(SyslogError), OTelSeverity: OTelSeverityError},
+		LevelCritical:  {Name: "critical", Aliases: []string{"crit"}, Color: ColorBgRed + ColorTextWhite, SyslogSeverity: Syslog(SyslogCritical), OTelSeverity: OTelSeverityError + 2},
+		LevelAlert:     {Name: "alert", Color: ColorBgYellow + ColorTextBlack, SyslogSeverity: Syslog(SyslogAlert), OTelSeverity: OTelSeverityError + 3},
+		LevelFatal:     {Name: "fatal", Color: ColorTextPurple, SyslogSeverity: Syslog(SyslogAlert), OTelSeverity: OTelSeverityFatal},
+		LevelPanic:     {Name: "panic", Color: ColorBgMagenta + ColorTextWhite, SyslogSeverity: Syslog(SyslogAlert), OTelSeverity: OTelSeverityFatal + 1},
+		LevelEmergency: {Name: "emergency", Aliases: []string{"emerg"}, Color: ColorBgRed + ColorTextYellow, SyslogSeverity: Syslog(SyslogEmergency), OTelSeverity: OTelSeverityFatal + 3},
+		LevelPrint:     {Name: "print", Color: ColorReset, SyslogSeverity: Syslog(SyslogNotice)},
+	}
+	for level, spec := range builtin {
+		_ = r.register(level, spec)
+	}
+	return r
+}
+
+// Register adds or replaces the spec of a level. It fails if the name is empty or the name
+// or an alias is already used by another level.
+func (r *LevelRegistry) Register(level Level, spec LevelSpec) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	err := r.register(level, spec)
+	if err == nil && r.onChange != nil {
+		r.onChange(r)
+	}
+	return err
+}
+
+// register must be called with the lock held.
+func (r *LevelRegistry) register(level Level, spec LevelSpec) error {
+	if strings.TrimSpace(spec.Name) == "" {
+		return errors.New("level %d needs a name", int(level))
+	}
+	if spec.Rank != nil && *spec.Rank == level {
+		return errors.New("level %d cannot rank after itself", int(level))
+	}
+	names := append([]string{spec.Name}, spec.Aliases...)
+	for _, name := range names {
+		key := strings.ToLower(strings.TrimSpace(name))
+		if other, ok := r.names[key]; ok && other != level {
+			return errors.New("level name %q is already used by level %d", name, int(other))
+		}
+		if _, err := strconv.Atoi(key); err == nil {
+			return errors.New("level name %q must not be a number", name)
+		}
+	}
+
+	r.unregister(level)
+	r.specs[level] = spec.clone()
+	for _, name := range names {
+		r.names[strings.ToLower(strings.TrimSpace(name))] = level
+	}
+	return nil
+}
+
+// Unregister removes a level. It is then rendered as described for Name.
+func (r *LevelRegistry) Unregister(level Level) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.unregister(level)
+	if r.onChange != nil {
+		r.onChange(r)
+	}
+}
+
+// unregister must be called with the lock held.
+func (r *LevelRegistry) unregister(level Level) {
+	delete(r.specs, level)
+	for name, other := range r.names {
+		if other == level {
+			delete(r.names, name)
+		}
+	}
+}
+
+// Lookup returns the spec of a registered level.
+func (r *LevelRegistry) Lookup(level Level) (LevelSpec, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	spec, ok := r.specs[level]
+	return spec.clone(), ok
+}
+
+// Levels returns the registered levels, from the most verbose.
+func (r *LevelRegistry) Levels() []Level {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.levels()
+}
+
+// levels must be called with the lock held.
+func (r *LevelRegistry) levels() []Level {
+	levels := make([]Level, 0, len(r.specs))
+	for level := range r.specs {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i].Compare(levels[j]) < 0 })
+	return levels
+}
+
+// Name returns the name of a level. A level more verbose than every registered level is
+// named relative to the most verbose one, as in "debug-2"; other unregistered levels are
+// rendered as "level(7)". ParseLevel accepts both forms.
+func (r *LevelRegistry) Name(level Level) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if spec, ok := r.specs[level]; ok {
+		return spec.Name
+	}
+	if levels := r.levels(); len(levels) > 0 && level.Compare(levels[0]) < 0 {
+		return r.specs[levels[0]].Name + "-" + strconv.Itoa(int(levels[0]-level))
+	}
+	return "level(" + strconv.Itoa(int(level)) + ")"
+}
+
+// Color returns the console color of a level, or ColorReset if it has none.
+func (r *LevelRegistry) Color(level Level) Color {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if spec, ok := r.specs[level]; ok && spec.Color != "" {
+		return spec.Color
+	}
+	return ColorReset
+}
+
+// Parse returns the level with the given name or alias, matched case-insensitively. It also
+// accepts a name with an offset, such as "debug-2" or "info+1", "level(7)" and plain numbers.
+func (r *LevelRegistry) Parse(name string) (Level, error) {
+	key := strings.ToLower(strings.TrimSpace(name))
+	if n, err := strconv.Atoi(key); err == nil {
+		return Level(n), nil
+	}
+	if strings.HasPrefix(key, "level(") && strings.HasSuffix(key, ")") {
+		if n, err := strconv.Atoi(key[len("level(") : len(key)-1]); err == nil {
+			return Level(n), nil
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if level, ok := r.names[key]; ok {
+		return level, nil
+	}
+	if i := strings.LastIndexAny(key, "+-"); i > 0 {
+		base, ok := r.names[key[:i]]
+		offset, err := strconv.Atoi(key[i:])
+		if ok && err == nil {
+			return base + Level(offset), nil
+		}
+	}
+	return 0, errors.New("unknown level %q", name)
+}
+
+// SyslogSeverity returns the syslog severity of a level, taken from the nearest registered
+// level below it if it has none. Levels below every registered level are SyslogDebug.
+func (r *LevelRegistry) SyslogSeverity(level Level) SyslogSeverity {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if spec, ok := r.nearest(level, func(spec LevelSpec) bool { return spec.SyslogSeverity != nil }); ok {
+		return *spec.SyslogSeverity
+	}
+	return SyslogDebug
+}
+
+// OTelSeverity returns the OpenTelemetry severity number of a level, taken from the nearest
+// registered level below it if it has none. Levels more verbose than every registered level
+// count down into the TRACE range. LevelPrint is OTelSeverityUnspecified unless registered
+// with a severity.
+func (r *LevelRegistry) OTelSeverity(level Level) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if spec, ok := r.specs[level]; ok && spec.OTelSeverity != 0 {
+		return spec.OTelSeverity
+	}
+	if level == LevelPrint {
+		return OTelSeverityUnspecified
+	}
+	hasSeverity := func(spec LevelSpec) bool { return spec.OTelSeverity != 0 }
+	if spec, ok := r.nearest(level, hasSeverity); ok {
+		return spec.OTelSeverity
+	}
+
+	// More verbose than every level with a severity: TRACE4 just below it, down to TRACE
+	for _, lowest := range r.levels() {
+		if spec := r.specs[lowest]; hasSeverity(spec) {
+			severity := spec.OTelSeverity - int(lowest-level)
+			if severity < OTelSeverityTrace {
+				severity = OTelSeverityTrace
+			}
+			return severity
+		}
+	}
+	return OTelSeverityUnspecified
+}
+
+// nearest returns the spec of the most severe registered level at or below level for which
+// has reports true. LevelPrint is skipped for other levels, since it is not a severity.
+// It must be called with the lock held.
+func (r *LevelRegistry) nearest(level Level, has func(LevelSpec) bool) (LevelSpec, bool) {
+	levels := r.levels()
+	for i := len(levels) - 1; i >= 0; i-- {
+		candidate := levels[i]
+		if candidate.Compare(level) > 0 || (candidate == LevelPrint && level != LevelPrint) {
+			continue
+		}
+		if spec := r.specs[candidate]; has(spec) {
+			return spec, true
+		}
+	}
+	return LevelSpec{}, false
+}
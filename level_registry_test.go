@@ -0,0 +1,199 @@
+package logos
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelRegistry(t *testing.T) {
+	r := NewLevelRegistry()
+	const LevelFinest, LevelAudit = LevelTrace - 2, LevelPanic + 1
+	assert.NoError(t, r.Register(LevelFinest, LevelSpec{Name: "finest", Aliases: []string{"fine"}, Color: ColorTextCyan}))
+	assert.NoError(t, r.Register(LevelAudit, LevelSpec{Name: "audit", SyslogSeverity: Syslog(SyslogNotice), OTelSeverity: 11}))
+
+	assert.Equal(t, "finest", r.Name(LevelFinest))
+	assert.Equal(t, ColorTextCyan, r.Color(LevelFinest))
+	assert.Equal(t, ColorReset, r.Color(LevelAudit))
+	assert.Equal(t, []Level{LevelFinest, LevelTrace, LevelDebug, LevelInfo, LevelNotice, LevelWarn, LevelError,
+		LevelCritical, LevelAlert, LevelFatal, LevelPanic, LevelEmergency, LevelAudit, LevelPrint}, r.Levels())
+
+	// Unregistered levels keep their value
+	assert.Equal(t, "finest-2", r.Name(LevelFinest-2))
+	assert.Equal(t, "level(12)", r.Name(Level(12)))
+	assert.Equal(t, "level(-3)", r.Name(LevelTrace-1))
+	for name, want := range map[string]Level{"FINE": LevelFinest, "finest-2": LevelFinest - 2, "info+1": LevelWarn, "level(12)": Level(12), "warning": LevelWarn} {
+		level, err := r.Parse(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, level, name)
+	}
+	_, err := r.Parse("finest-")
+	assert.EqualError(t, err, `unknown level "finest-"`)
+
+	// Severities left at zero come from the nearest registered level below
+	assert.Equal(t, SyslogDebug, r.SyslogSeverity(LevelFinest))
+	assert.Equal(t, OTelSeverityTrace, r.OTelSeverity(LevelFinest))
+	assert.Equal(t, SyslogError, r.SyslogSeverity(LevelError))
+	assert.Equal(t, SyslogNotice, r.SyslogSeverity(Level(50)))
+	assert.Equal(t, 11, r.OTelSeverity(Level(50)))
+	assert.Equal(t, OTelSeverityUnspecified, r.OTelSeverity(LevelPrint))
+
+	// Names belong to one level; re-registering replaces the old names
+	assert.Error(t, r.Register(Level(12), LevelSpec{Name: "Fine"}))
+	assert.Error(t, r.Register(Level(12), LevelSpec{}))
+	assert.Error(t, r.Register(Level(12), LevelSpec{Name: "42"}))
+	assert.NoError(t, r.Register(LevelFinest, LevelSpec{Name: "finest"}))
+	_, err = r.Parse("fine")
+	assert.Error(t, err)
+	r.Unregister(LevelAudit)
+	assert.Equal(t, "level(9)", r.Name(LevelAudit))
+
+	// The package registry is unaffected
+	assert.Equal(t, "trace-2", LevelFinest.String())
+}
+
+func TestLevelRegistry_Builtin(t *testing.T) {
+	r := NewLevelRegistry()
+	tests := []struct {
+		level  Level
+		name   string
+		syslog SyslogSeverity
+		otel   int
+		gcp    string
+	}{
+		{LevelTrace, "trace", SyslogDebug, OTelSeverityTrace, "DEBUG"},
+		{LevelDebug, "debug", SyslogDebug, OTelSeverityDebug, "DEBUG"},
+		{LevelInfo, "info", SyslogInformational, OTelSeverityInfo, "INFO"},
+		{LevelNotice, "notice", SyslogNotice, OTelSeverityInfo + 1, "NOTICE"},
+		{LevelWarn, "warn", SyslogWarning, OTelSeverityWarn, "WARNING"},
+		{LevelError, "error", SyslogError, OTelSeverityError, "ERROR"},
+		{LevelCritical, "critical", SyslogCritical, OTelSeverityError + 2, "CRITICAL"},
+		{LevelAlert, "alert", SyslogAlert, OTelSeverityError + 3, "ALERT"},
+		{LevelFatal, "fatal", SyslogAlert, OTelSeverityFatal, "ALERT"},
+		{LevelPanic, "panic", SyslogAlert, OTelSeverityFatal + 1, "ALERT"},
+		{LevelEmergency, "emergency", SyslogEmergency, OTelSeverityFatal + 3, "EMERGENCY"},
+	}
+	cfg := DefaultConfig
+	cfg.LevelRegistry = r
+	for _, tt := range tests {
+		assert.Equal(t, tt.name, r.Name(tt.level))
+		assert.NotEqual(t, ColorReset, r.Color(tt.level), tt.name)
+		assert.Equal(t, tt.syslog, r.SyslogSeverity(tt.level), tt.name)
+		assert.Equal(t, tt.otel, r.OTelSeverity(tt.level), tt.name)
+		assert.Equal(t, tt.gcp, gcpSeverity(tt.level, &cfg), tt.name)
+	}
+
+	// Values that predate the ladder are unchanged
+	assert.Equal(t, []Level{-1, 0, 1, 2, 3}, []Level{LevelDebug, LevelInfo, LevelWarn, LevelError, LevelFatal})
+	assert.Equal(t, []SyslogSeverity{0, 7}, []SyslogSeverity{SyslogEmergency, SyslogDebug})
+
+	// SyslogEmergency is zero but still a severity; only a nil one is taken from below
+	assert.NoError(t, r.Register(LevelTrace-5, LevelSpec{Name: "quiet"}))
+	assert.NoError(t, r.Register(LevelTrace-4, LevelSpec{Name: "loud", SyslogSeverity: Syslog(SyslogEmergency)}))
+	assert.Equal(t, SyslogDebug, r.SyslogSeverity(LevelTrace-5))
+	assert.Equal(t, SyslogEmergency, r.SyslogSeverity(LevelTrace-4))
+
+	// Registered specs are copies
+	severity := SyslogAlert
+	assert.NoError(t, r.Register(LevelTrace-3, LevelSpec{Name: "copied", SyslogSeverity: &severity}))
+	severity = SyslogDebug
+	spec, _ := r.Lookup(LevelTrace - 3)
+	*spec.SyslogSeverity = SyslogDebug
+	assert.Equal(t, SyslogAlert, r.SyslogSeverity(LevelTrace-3))
+	for name, want := range map[string]Level{"crit": LevelCritical, "emerg": LevelEmergency, "err": LevelError} {
+		level, err := r.Parse(name)
+		assert.NoError(t, err, name)
+		assert.Equal(t, want, level, name)
+	}
+}
+
+func TestRegisterLevel(t *testing.T) {
+	const LevelFinest = LevelTrace - 1
+	assert.NoError(t, RegisterLevel(LevelFinest, LevelSpec{Name: "finest", Color: ColorTextCyan, OTelSeverity: OTelSeverityTrace}))
+	t.Cleanup(func() { UnregisterLevel(LevelFinest) })
+
+	assert.Equal(t, "finest", LevelFinest.String())
+	assert.Equal(t, "finest", LevelNames[LevelFinest])
+	assert.Equal(t, LevelFinest, DefaultLevels["finest"])
+	level, err := ParseLevel("FINEST")
+	assert.NoError(t, err)
+	assert.Equal(t, LevelFinest, level)
+
+	buf := &bytes.Buffer{}
+	NewLogger(LevelFinest, OTelFormatter(), buf).Log(LevelFinest, "deep")
+	assert.Equal(t, "finest", Map(buf)["severityText"])
+	NewLogger(LevelFinest, GCPFormatter(), buf).Log(LevelFinest, "deep")
+	assert.Equal(t, "DEBUG", Map(buf)["severity"])
+	NewLogger(LevelFinest, ConsoleFormatter(), buf).Log(LevelFinest, "deep")
+	assert.Contains(t, buf.String(), string(ColorTextCyan)+"finest")
+}
+
+func TestRegisterLevel_Rank(t *testing.T) {
+	const LevelAudit = LevelPanic + 1
+	const LevelAudit2 = LevelPanic + 2
+	assert.NoError(t, RegisterLevel(LevelAudit, LevelSpec{Name: "audit", Rank: After(LevelNotice)}))
+	assert.NoError(t, RegisterLevel(LevelAudit2, LevelSpec{Name: "audit2", Rank: After(LevelAudit)}))
+	t.Cleanup(func() {
+		UnregisterLevel(LevelAudit2)
+		UnregisterLevel(LevelAudit)
+	})
+
+	// Ranked after notice and before warn, however high the value
+	assert.Equal(t, 1, LevelAudit.Compare(LevelNotice))
+	assert.Equal(t, -1, LevelAudit.Compare(LevelWarn))
+	assert.Equal(t, -1, LevelAudit.Compare(LevelAudit2))
+	assert.Equal(t, -1, LevelAudit2.Compare(LevelWarn))
+	assert.True(t, LevelAudit.AtLeast(LevelNotice))
+	assert.False(t, LevelAudit.AtLeast(LevelWarn))
+
+	buf := &bytes.Buffer{}
+	NewLogger(LevelWarn, JSONFormatter(), buf).Log(LevelAudit, "dropped")
+	assert.Empty(t, buf.String())
+	NewLogger(LevelNotice, OTelFormatter(), buf).Log(LevelAudit, "written")
+	assert.Equal(t, float64(OTelSeverityInfo+1), Map(buf)["severityNumber"]) // Taken from notice
+
+	// Unregistered, it ranks by value again
+	UnregisterLevel(LevelAudit2)
+	assert.Equal(t, 1, LevelAudit2.Compare(LevelEmergency))
+
+	assert.Error(t, RegisterLevel(LevelAudit2, LevelSpec{Name: "audit2", Rank: After(LevelAudit2)}))
+}
+
+func TestRegisterLevel_Concurrent(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		level := LevelTrace - 10 - Level(i)
+		name := "concurrent" + strconv.Itoa(i)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, RegisterLevel(level, LevelSpec{Name: name}))
+			UnregisterLevel(level)
+		}()
+	}
+	wg.Wait()
+
+	levelRegistry.mu.RLock()
+	defer levelRegistry.mu.RUnlock()
+	assert.NotContains(t, LevelNames, LevelTrace-10)
+	assert.Equal(t, "warn", LevelNames[LevelWarn])
+}
+
+func TestConfig_LevelRegistry(t *testing.T) {
+	r := NewLevelRegistry()
+	assert.NoError(t, r.Register(LevelError, LevelSpec{Name: "ERR", SyslogSeverity: Syslog(SyslogAlert)}))
+	cfg := DefaultConfig
+	cfg.LevelRegistry = r
+
+	buf := &bytes.Buffer{}
+	NewLogger(LevelInfo, NewJsonFormatter(cfg), buf).Error("scoped")
+	assert.Equal(t, "ERR", Map(buf)["level"])
+	NewLogger(LevelInfo, NewGCPFormatter(cfg), buf).Error("scoped")
+	assert.Equal(t, "ALERT", Map(buf)["severity"])
+
+	NewLogger(LevelInfo, JSONFormatter(), buf).Error("global")
+	assert.Equal(t, "error", Map(buf)["level"])
+}
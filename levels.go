@@ -1,29 +1,24 @@
 package logos
 
 import (
+	"encoding/json"
 	"math"
-	"sync"
+	"sync/atomic"
+
+	"github.com/goodblaster/errors"
 )
 
-// Level represents the severity of a log message.
+// Level represents the severity of a log message. Loggers filter with Compare, which orders
+// the built-in levels by severity: trace, debug, info, notice, warn, error, critical, alert,
+// fatal, panic, emergency. The values of LevelDebug through LevelFatal predate the rest of
+// the ladder and are unchanged, so LevelNotice through LevelPanic have values above
+// LevelFatal but rank where they belong. Levels registered with a LevelSpec.Rank rank right
+// after that level; other levels rank by value.
 type Level int
 
-// String returns the string representation of a logging level.
+// String returns the name of the level in the package registry; see LevelRegistry.Name.
 func (level Level) String() string {
-	levelMu.RLock()
-	name, ok := LevelNames[level]
-	levelMu.RUnlock()
-
-	if ok {
-		return name
-	}
-
-	// Keep "print" as a default option.
-	if level == LevelPrint {
-		return "print"
-	}
-
-	return "unknown"
+	return levelRegistry.Name(level)
 }
 
 const (
@@ -37,114 +32,283 @@ const (
 	LevelError
 	// LevelFatal represents very severe error events that will presumably lead the application to abort.
 	LevelFatal
+	// LevelNotice represents normal but significant events, such as a configuration change.
+	LevelNotice
+	// LevelCritical represents critical conditions, such as a failed dependency.
+	LevelCritical
+	// LevelAlert represents conditions that need immediate action, such as a corrupted store.
+	LevelAlert
+	// LevelEmergency represents a system that is unusable.
+	LevelEmergency
+	// LevelPanic represents errors that abort the current goroutine, logged before a panic.
+	LevelPanic
 	// LevelPrint is used for messages that should always be printed regardless of level filtering.
 	LevelPrint = math.MaxInt
 )
 
-// LevelNames maps Level values to human-readable strings. This can be overridden by the user.
-// Access is protected by levelMu for thread safety.
-var LevelNames map[Level]string
+// LevelTrace represents information finer than debug, such as function entry and exit.
+const LevelTrace = LevelDebug - 1
 
-// LevelColors maps Level values to ANSI color codes for console output. This can be customized.
-// Access is protected by levelMu for thread safety.
-var LevelColors map[Level]Color
+// levelRank is the position of a level in the severity order: a level ranking by value and a
+// step after it.
+type levelRank struct {
+	base Level
+	step int
+}
 
-// levelMu protects concurrent access to LevelNames and LevelColors.
-var levelMu sync.RWMutex
+// levelRanks holds the ranks of the levels in the package registry with a LevelSpec.Rank.
+// It is replaced by mirrorLevels, so loggers read it without locking.
+var levelRanks atomic.Pointer[map[Level]levelRank]
 
-// DefaultLevels provides a default mapping for logging level strings to their corresponding Level values.
-var DefaultLevels = map[string]Level{
-	"debug": LevelDebug,
-	"info":  LevelInfo,
-	"warn":  LevelWarn,
-	"error": LevelError,
-	"fatal": LevelFatal,
-	"print": LevelPrint,
+// builtinRank returns the rank of a level without consulting the registry. The levels added
+// after LevelFatal follow the older level below them; every other level ranks by value.
+// Built-in steps leave room for registered levels ranked after them.
+func builtinRank(level Level) levelRank {
+	switch level {
+	case LevelNotice:
+		return levelRank{LevelInfo, 100}
+	case LevelCritical:
+		return levelRank{LevelError, 100}
+	case LevelAlert:
+		return levelRank{LevelError, 200}
+	case LevelPanic:
+		return levelRank{LevelFatal, 100}
+	case LevelEmergency:
+		return levelRank{LevelFatal, 200}
+	}
+	return levelRank{level, 0}
 }
 
-// init initializes default level names and associated colors.
+// rank returns the position of a level in the severity order.
+func (level Level) rank() levelRank {
+	if ranks := levelRanks.Load(); ranks != nil {
+		if rank, ok := (*ranks)[level]; ok {
+			return rank
+		}
+	}
+	return builtinRank(level)
+}
+
+// Compare returns -1, 0 or +1 as level is less severe than, as severe as or more severe
+// than other. Levels ranked right after the same level are ordered by value.
+func (level Level) Compare(other Level) int {
+	rank, otherRank := level.rank(), other.rank()
+	switch {
+	case rank.base != otherRank.base:
+		return compareInts(int(rank.base), int(otherRank.base))
+	case rank.step != otherRank.step:
+		return compareInts(rank.step, otherRank.step)
+	}
+	return compareInts(int(level), int(other))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// AtLeast reports whether level is at least as severe as threshold, which is how loggers
+// decide whether to write an entry.
+func (level Level) AtLeast(threshold Level) bool {
+	return level.Compare(threshold) >= 0
+}
+
+// levelRegistry is the package registry, changed with RegisterLevel.
+var levelRegistry = NewLevelRegistry()
+
+// LevelNames, LevelColors and DefaultLevels mirror the package registry for code reading
+// them. They are replaced whenever it changes, so reading them while levels are being
+// registered is not safe; changing them has no effect.
+//
+// Deprecated: Use RegisterLevel and LookupLevel, or a Config's LevelRegistry.
+var (
+	LevelNames    map[Level]string
+	LevelColors   map[Level]Color
+	DefaultLevels map[string]Level
+)
+
+// init keeps the deprecated maps in sync with the package registry.
 func init() {
-	LevelNames = map[Level]string{
-		LevelDebug: "debug",
-		LevelInfo:  "info",
-		LevelWarn:  "warn",
-		LevelError: "error",
-		LevelFatal: "fatal",
-		LevelPrint: "print",
+	levelRegistry.mu.Lock()
+	defer levelRegistry.mu.Unlock()
+	levelRegistry.onChange = mirrorLevels
+	mirrorLevels(levelRegistry)
+}
+
+// mirrorLevels rebuilds the deprecated maps from the registry. It is called with the
+// registry's write lock held, so concurrent changes replace the maps one at a time.
+func mirrorLevels(r *LevelRegistry) {
+	names := make(map[Level]string, len(r.specs))
+	colors := make(map[Level]Color, len(r.specs))
+	otel := make(map[Level]int, len(r.specs))
+	for level, spec := range r.specs {
+		names[level] = spec.Name
+		colors[level] = spec.Color
+		otel[level] = spec.OTelSeverity
+	}
+	parse := make(map[string]Level, len(r.names))
+	for name, level := range r.names {
+		parse[name] = level
+	}
+	LevelNames, LevelColors, DefaultLevels, OTelSeverities = names, colors, parse, otel
+	levelRanks.Store(rankLevels(r.specs))
+}
+
+// rankLevels returns the ranks of the levels with a LevelSpec.Rank: one step after the level
+// they are ranked after. A level in a cycle of Rank settings ranks by value.
+func rankLevels(specs map[Level]LevelSpec) *map[Level]levelRank {
+	ranks := map[Level]levelRank{}
+	var resolve func(level Level, depth int) (levelRank, bool)
+	resolve = func(level Level, depth int) (levelRank, bool) {
+		if rank, ok := ranks[level]; ok {
+			return rank, true
+		}
+		spec, ok := specs[level]
+		if !ok || spec.Rank == nil {
+			return builtinRank(level), true
+		}
+		if depth > len(specs) {
+			return levelRank{}, false
+		}
+		after, ok := resolve(*spec.Rank, depth+1)
+		if !ok {
+			return levelRank{}, false
+		}
+		rank := levelRank{after.base, after.step + 1}
+		ranks[level] = rank
+		return rank, true
 	}
+	for level, spec := range specs {
+		if spec.Rank != nil {
+			resolve(level, 0)
+		}
+	}
+	return &ranks
+}
+
+// RegisterLevel adds or replaces a level in the package registry, which every formatter
+// without a registry of its own uses for names, colors and severities:
+//
+//	const LevelAudit = logos.LevelPanic + 1 // Values above LevelPanic are free
+//	logos.RegisterLevel(LevelAudit, logos.LevelSpec{Name: "audit", Color: logos.ColorTextCyan,
+//		Rank: logos.After(logos.LevelNotice), OTelSeverity: logos.OTelSeverityInfo + 2})
+func RegisterLevel(level Level, spec LevelSpec) error {
+	return levelRegistry.Register(level, spec)
+}
+
+// UnregisterLevel removes a level from the package registry.
+func UnregisterLevel(level Level) {
+	levelRegistry.Unregister(level)
+}
+
+// LookupLevel returns the spec of a level in the package registry.
+func LookupLevel(level Level) (LevelSpec, bool) {
+	return levelRegistry.Lookup(level)
+}
 
-	LevelColors = map[Level]Color{
-		LevelDebug: ColorTextBlue,
-		LevelInfo:  ColorTextGreen,
-		LevelWarn:  ColorTextYellow,
-		LevelError: ColorTextRed,
-		LevelFatal: ColorTextPurple,
-		LevelPrint: ColorReset,
+// levels returns the registry used by formatters with this configuration.
+func (cfg *Config) levels() *LevelRegistry {
+	if cfg != nil && cfg.LevelRegistry != nil {
+		return cfg.LevelRegistry
 	}
+	return levelRegistry
 }
 
 // GetLevelName returns the name for a level, using the Config's LevelNames if present,
-// otherwise falling back to the global LevelNames map (with thread-safe access).
+// otherwise its registry (see Config.LevelRegistry).
 func GetLevelName(level Level, cfg *Config) string {
-	// Try config first
 	if cfg != nil && cfg.LevelNames != nil {
 		if name, ok := cfg.LevelNames[level]; ok {
 			return name
 		}
 	}
-
-	// Fall back to global with read lock
-	levelMu.RLock()
-	name, ok := LevelNames[level]
-	levelMu.RUnlock()
-
-	if ok {
-		return name
-	}
-
-	// Default for LevelPrint
-	if level == LevelPrint {
-		return "print"
-	}
-
-	return "unknown"
+	return cfg.levels().Name(level)
 }
 
 // GetLevelColor returns the color for a level, using the Config's LevelColors if present,
-// otherwise falling back to the global LevelColors map (with thread-safe access).
+// otherwise its registry (see Config.LevelRegistry).
 func GetLevelColor(level Level, cfg *Config) Color {
-	// Try config first
 	if cfg != nil && cfg.LevelColors != nil {
 		if color, ok := cfg.LevelColors[level]; ok {
 			return color
 		}
 	}
+	return cfg.levels().Color(level)
+}
 
-	// Fall back to global with read lock
-	levelMu.RLock()
-	color, ok := LevelColors[level]
-	levelMu.RUnlock()
+// SetLevelName sets the name of a level in the package registry, keeping its other settings.
+// An empty name unregisters the level.
+func SetLevelName(level Level, name string) {
+	if name == "" {
+		UnregisterLevel(level)
+		return
+	}
+	spec, _ := LookupLevel(level)
+	spec.Name = name
+	_ = RegisterLevel(level, spec)
+}
 
-	if ok {
-		return color
+// SetLevelColor sets the color of a registered level in the package registry. Unregistered
+// levels are registered with their current name.
+func SetLevelColor(level Level, color Color) {
+	spec, ok := LookupLevel(level)
+	if !ok {
+		spec.Name = level.String()
 	}
+	spec.Color = color
+	_ = RegisterLevel(level, spec)
+}
 
-	// Default to reset color if not found
-	return ColorReset
+// ParseLevel returns the level with the given name in the package registry; see
+// LevelRegistry.Parse.
+func ParseLevel(name string) (Level, error) {
+	return levelRegistry.Parse(name)
 }
 
-// SetLevelName sets a custom name for a level in the global LevelNames map.
-// This function is thread-safe.
-func SetLevelName(level Level, name string) {
-	levelMu.Lock()
-	defer levelMu.Unlock()
-	LevelNames[level] = name
+// MarshalText implements encoding.TextMarshaler, writing the level's name.
+func (level Level) MarshalText() ([]byte, error) {
+	return []byte(level.String()), nil
 }
 
-// SetLevelColor sets a custom color for a level in the global LevelColors map.
-// This function is thread-safe.
-func SetLevelColor(level Level, color Color) {
-	levelMu.Lock()
-	defer levelMu.Unlock()
-	LevelColors[level] = color
+// UnmarshalText implements encoding.TextUnmarshaler using ParseLevel.
+func (level *Level) UnmarshalText(text []byte) error {
+	parsed, err := ParseLevel(string(text))
+	if err != nil {
+		return err
+	}
+	*level = parsed
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, writing the level as a string.
+func (level Level) MarshalJSON() ([]byte, error) {
+	text, err := level.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting a level name or a number.
+func (level *Level) UnmarshalJSON(data []byte) error {
+	var n int
+	if err := json.Unmarshal(data, &n); err == nil {
+		*level = Level(n)
+		return nil
+	}
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		return errors.Wrap(err, "level must be a string or a number")
+	}
+	return level.UnmarshalText([]byte(name))
+}
+
+// Set implements flag.Value using ParseLevel.
+func (level *Level) Set(name string) error {
+	return level.UnmarshalText([]byte(name))
 }
@@ -4,21 +4,37 @@ import (
 	"fmt"
 	"io"
 	"sync"
+	"time"
 )
 
 // Fields represents a key-value pair used to annotate log entries.
 type Fields = map[string]any
 
+// Well-known field keys. Formatters that follow a vendor convention (such as GCPFormatter)
+// move these out of the regular fields and into the slots that convention defines.
+const (
+	FieldTraceID      = "trace_id"      // Hex-encoded trace ID
+	FieldSpanID       = "span_id"       // Hex-encoded span ID
+	FieldTraceSampled = "trace_sampled" // Whether the trace was sampled
+	FieldHTTPRequest  = "http_request"  // HTTPRequest describing an access-log entry
+)
+
 // Logger is the primary struct for logging messages with optional fields and errors.
 type Logger struct {
-	level       *Level
-	formatter   Formatter
-	writer      io.Writer
-	sync        *sync.Mutex
-	fields      Fields
-	error       error
-	teeLoggers  []Logger
+	level        *Level
+	formatter    Formatter
+	writer       io.Writer
+	sync         *sync.Mutex
+	fields       Fields
+	error        error
+	teeLoggers   []Logger
 	errorHandler func(error) // Called when write errors occur
+	caller       bool        // Capture the calling source location for each entry
+	name         string      // Dotted logger name, set with Named
+	transformers []Transformer
+	groups       []string    // Group path for fields added with WithFields, set with WithGroup
+	sampler      Sampler     // Drops some entries for this logger's destination, set with WithSampler
+	live         *liveConfig // Outputs and levels of a logger built from a LoggerConfig
 }
 
 // NewLogger creates a new Logger instance with the given level, formatter, and output writer.
@@ -43,6 +59,9 @@ func (logger Logger) GetLevel() Level {
 	if logger.level == nil {
 		return LevelInfo // Safe default
 	}
+	if logger.live != nil && *logger.level == levelUnset {
+		return logger.live.state.Load().levelFor(logger.name)
+	}
 	return *logger.level
 }
 
@@ -75,7 +94,10 @@ func (logger Logger) IsLevelEnabled(level Level) bool {
 	if logger.level == nil {
 		return false
 	}
-	return *logger.level <= level
+	if logger.live != nil {
+		return logger.live.enabled(logger, level)
+	}
+	return level.AtLeast(*logger.level)
 }
 
 // WithLevel returns a new Logger with the specified logging level.
@@ -100,10 +122,20 @@ func (logger Logger) Copy() Logger {
 	newLogger := Logger{
 		level:        &newLevel,           // New level pointer - independent
 		formatter:    logger.formatter,    // Formatters are immutable, safe to share
-		writer:       logger.writer,        // Writers are external, shared by design
-		sync:         logger.sync,          // Shared mutex - protects the shared writer
-		error:        logger.error,         // Errors are immutable, safe to share
+		writer:       logger.writer,       // Writers are external, shared by design
+		sync:         logger.sync,         // Shared mutex - protects the shared writer
+		error:        logger.error,        // Errors are immutable, safe to share
 		errorHandler: logger.errorHandler, // Error handler function, safe to share
+		caller:       logger.caller,
+		name:         logger.name,
+		groups:       logger.groups, // Never mutated, WithGroup appends to a copy
+		sampler:      logger.sampler,
+		live:         logger.live, // Shared so that reloaded configuration reaches every copy
+	}
+
+	// Transformers are applied in order and never mutated, but appending must not share backing arrays
+	if len(logger.transformers) > 0 {
+		newLogger.transformers = append([]Transformer(nil), logger.transformers...)
 	}
 
 	// Deep copy fields
@@ -130,7 +162,8 @@ func (logger Logger) With(key string, value any) Logger {
 	return logger.WithFields(Fields{key: value})
 }
 
-// WithFields returns a new Logger with additional key-value pairs.
+// WithFields returns a new Logger with additional key-value pairs, nested under the
+// logger's group if WithGroup was used.
 func (logger Logger) WithFields(fields Fields) Logger {
 	newLogger := logger.Copy()
 
@@ -138,8 +171,12 @@ func (logger Logger) WithFields(fields Fields) Logger {
 		newLogger.fields = make(Fields)
 	}
 
+	target := newLogger.fields
+	if len(newLogger.groups) > 0 && len(fields) > 0 {
+		target = groupFields(newLogger.fields, newLogger.groups)
+	}
 	for key, value := range fields {
-		newLogger.fields[key] = value
+		target[key] = value
 	}
 
 	return newLogger
@@ -153,6 +190,32 @@ func (logger Logger) WithError(err error) Logger {
 	return newLogger
 }
 
+// Named returns a new Logger with the given name appended to the logger's name,
+// separated by a dot (e.g. "app" then "db" gives "app.db"). The name is available to
+// formatters as Entry.Name.
+func (logger Logger) Named(name string) Logger {
+	newLogger := logger.Copy()
+	if newLogger.name == "" {
+		newLogger.name = name
+	} else if name != "" {
+		newLogger.name += "." + name
+	}
+	return newLogger
+}
+
+// GetName returns the logger's name.
+func (logger Logger) GetName() string {
+	return logger.name
+}
+
+// WithCaller returns a new Logger that records the source location of each log call
+// in Entry.Caller. Caller capture walks the stack, so it is disabled by default.
+func (logger Logger) WithCaller(enabled bool) Logger {
+	newLogger := logger.Copy()
+	newLogger.caller = enabled
+	return newLogger
+}
+
 // WithErrorHandler returns a new Logger with the specified error handler.
 // The error handler is called whenever a write error occurs during logging.
 // If handler is nil, write errors will be silently ignored.
@@ -164,54 +227,65 @@ func (logger Logger) WithErrorHandler(handler func(error)) Logger {
 
 // Log logs a message at the specified level.
 func (logger Logger) Log(level Level, a ...any) {
-	// Defensive nil checks
-	if logger.level == nil || logger.formatter == nil || logger.writer == nil {
-		return
-	}
-
-	msg := fmt.Sprint(a...)
-	entry := Entry{
-		Fields: logger.fields,
-		Msg:    msg,
-		Error:  logger.error,
-	}
+	logger.log(level, fmt.Sprint(a...))
+}
 
-	// Write to main writer if level is enabled
-	if *logger.level <= level {
-		line := logger.formatter.Format(level, entry)
-		// Lock to prevent concurrent writes to the same writer (e.g., bytes.Buffer)
-		logger.sync.Lock()
-		_, err := fmt.Fprintln(logger.writer, line)
-		logger.sync.Unlock()
+// Logf logs a formatted message at the specified level.
+func (logger Logger) Logf(level Level, format string, args ...any) {
+	logger.log(level, fmt.Sprintf(format, args...))
+}
 
-		// Call error handler if write failed and handler is set
-		if err != nil && logger.errorHandler != nil {
-			logger.errorHandler(err)
-		}
-	}
+// log writes an already-rendered message to the main writer and every tee logger.
+func (logger Logger) log(level Level, msg string) {
+	logger.dispatch(level, msg, nil, nil)
+}
 
-	// Always call Log on each tee logger (they handle their own level checking and formatting)
-	for _, teeLogger := range logger.teeLoggers {
-		teeLogger.Log(level, a...)
+// dispatch writes a message using the logger's transformers preceded by those inherited
+// from parent loggers, then passes both on to each tee logger. Call fields, such as those
+// extracted from a context, are added to every entry; the logger's own fields take precedence.
+func (logger Logger) dispatch(level Level, msg string, callFields Fields, inherited []Transformer) {
+	if logger.live != nil && logger.level != nil {
+		logger.live.dispatch(logger, level, msg, callFields, inherited)
+		return
 	}
-}
 
-// Logf logs a formatted message at the specified level.
-func (logger Logger) Logf(level Level, format string, args ...any) {
 	// Defensive nil checks
 	if logger.level == nil || logger.formatter == nil || logger.writer == nil {
 		return
 	}
 
-	msg := fmt.Sprintf(format, args...)
-	entry := Entry{
-		Fields: logger.fields,
-		Msg:    msg,
-		Error:  logger.error,
+	transformers := inherited
+	if len(logger.transformers) > 0 {
+		transformers = append(inherited[:len(inherited):len(inherited)], logger.transformers...)
 	}
 
-	// Write to main writer if level is enabled
-	if *logger.level <= level {
+	// Write to main writer if level is enabled and the sampler, if any, keeps the entry
+	if level.AtLeast(*logger.level) && (logger.sampler == nil || level == LevelPrint || logger.sampler.Sample(level, msg)) {
+		fields := logger.fields
+		if len(callFields) > 0 {
+			fields = make(Fields, len(callFields)+len(logger.fields))
+			for key, value := range callFields {
+				fields[key] = value
+			}
+			for key, value := range logger.fields {
+				fields[key] = value
+			}
+		}
+
+		entry := Entry{
+			Fields: resolveFields(fields),
+			Msg:    msg,
+			Error:  logger.error,
+			Time:   time.Now(),
+			Name:   logger.name,
+		}
+		if logger.caller {
+			entry.Caller = callerOf()
+		}
+		for _, t := range transformers {
+			entry = t.Transform(level, entry)
+		}
+
 		line := logger.formatter.Format(level, entry)
 		// Lock to prevent concurrent writes to the same writer (e.g., bytes.Buffer)
 		logger.sync.Lock()
@@ -224,32 +298,35 @@ func (logger Logger) Logf(level Level, format string, args ...any) {
 		}
 	}
 
-	// Always call Logf on each tee logger (they handle their own level checking and formatting)
+	// Always log to each tee logger (they handle their own level checking and formatting)
 	for _, teeLogger := range logger.teeLoggers {
-		teeLogger.Logf(level, format, args...)
+		teeLogger.dispatch(level, msg, callFields, transformers)
 	}
 }
 
-// LogFunc evaluates the message-producing function only if at least one logger (main or tee) has the level enabled.
-func (logger Logger) LogFunc(level Level, msg func() string) {
+// anyEnabled reports whether the main logger or any tee logger would accept the level.
+func (logger Logger) anyEnabled(level Level) bool {
 	// Defensive nil check
 	if logger.level == nil {
-		return
+		return false
 	}
-
-	// Check if main logger or any tee logger would accept this level
-	shouldLog := *logger.level <= level
-	if !shouldLog {
-		for _, teeLogger := range logger.teeLoggers {
-			// Nil check for tee logger level
-			if teeLogger.level != nil && *teeLogger.level <= level {
-				shouldLog = true
-				break
-			}
+	if logger.live != nil && logger.live.enabled(logger, level) {
+		return true
+	}
+	if logger.live == nil && level.AtLeast(*logger.level) {
+		return true
+	}
+	for _, teeLogger := range logger.teeLoggers {
+		if teeLogger.anyEnabled(level) {
+			return true
 		}
 	}
+	return false
+}
 
-	if !shouldLog {
+// LogFunc evaluates the message-producing function only if at least one logger (main or tee) has the level enabled.
+func (logger Logger) LogFunc(level Level, msg func() string) {
+	if !logger.anyEnabled(level) {
 		return
 	}
 
@@ -258,24 +335,7 @@ func (logger Logger) LogFunc(level Level, msg func() string) {
 
 // LogIf calls the provided function if at least one logger (main or tee) has the level enabled.
 func (logger Logger) LogIf(level Level, log func()) {
-	// Defensive nil check
-	if logger.level == nil {
-		return
-	}
-
-	// Check if main logger or any tee logger would accept this level
-	shouldLog := *logger.level <= level
-	if !shouldLog {
-		for _, teeLogger := range logger.teeLoggers {
-			// Nil check for tee logger level
-			if teeLogger.level != nil && *teeLogger.level <= level {
-				shouldLog = true
-				break
-			}
-		}
-	}
-
-	if !shouldLog {
+	if !logger.anyEnabled(level) {
 		return
 	}
 
@@ -344,9 +404,74 @@ func (logger Logger) Fatalf(format string, args ...any) {
 	panic(fmt.Sprintf(format, args...))
 }
 
+// Trace logs a message at the trace level.
+func (logger Logger) Trace(a ...any) {
+	logger.Log(LevelTrace, a...)
+}
+
+// Tracef logs a formatted message at the trace level.
+func (logger Logger) Tracef(format string, args ...any) {
+	logger.Logf(LevelTrace, format, args...)
+}
+
+// Notice logs a message at the notice level.
+func (logger Logger) Notice(a ...any) {
+	logger.Log(LevelNotice, a...)
+}
+
+// Noticef logs a formatted message at the notice level.
+func (logger Logger) Noticef(format string, args ...any) {
+	logger.Logf(LevelNotice, format, args...)
+}
+
+// Critical logs a message at the critical level.
+func (logger Logger) Critical(a ...any) {
+	logger.Log(LevelCritical, a...)
+}
+
+// Criticalf logs a formatted message at the critical level.
+func (logger Logger) Criticalf(format string, args ...any) {
+	logger.Logf(LevelCritical, format, args...)
+}
+
+// Alert logs a message at the alert level.
+func (logger Logger) Alert(a ...any) {
+	logger.Log(LevelAlert, a...)
+}
+
+// Alertf logs a formatted message at the alert level.
+func (logger Logger) Alertf(format string, args ...any) {
+	logger.Logf(LevelAlert, format, args...)
+}
+
+// Emergency logs a message at the emergency level.
+func (logger Logger) Emergency(a ...any) {
+	logger.Log(LevelEmergency, a...)
+}
+
+// Emergencyf logs a formatted message at the emergency level.
+func (logger Logger) Emergencyf(format string, args ...any) {
+	logger.Logf(LevelEmergency, format, args...)
+}
+
+// Panic logs a message at the panic level and then panics.
+func (logger Logger) Panic(a ...any) {
+	logger.Log(LevelPanic, a...)
+	panic(fmt.Sprint(a...))
+}
+
+// Panicf logs a formatted message at the panic level and then panics.
+func (logger Logger) Panicf(format string, args ...any) {
+	logger.Logf(LevelPanic, format, args...)
+	panic(fmt.Sprintf(format, args...))
+}
+
 // Entry holds the log data including fields, message, and error.
 type Entry struct {
 	Fields Fields
 	Msg    string
 	Error  error
+	Time   time.Time // When the entry was logged. Zero if the formatter is called directly.
+	Caller *Caller   // Source location of the log call. Nil unless the logger was built WithCaller.
+	Name   string    // Name of the logger, set with Named.
 }
@@ -233,6 +233,57 @@ func TestLogger_Fatal_Panics(t *testing.T) {
 	})
 }
 
+func TestLogger_SeverityLadder(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := NewLogger(LevelTrace, JSONFormatter(), buf)
+
+	tests := map[string]func(){
+		"trace":     func() { log.Trace("msg") },
+		"notice":    func() { log.Noticef("%s", "msg") },
+		"critical":  func() { log.Critical("msg") },
+		"alert":     func() { log.Alertf("%s", "msg") },
+		"emergency": func() { log.Emergency("msg") },
+	}
+	for name, logMsg := range tests {
+		logMsg()
+		m := Map(buf)
+		assert.Equal(t, name, m["level"])
+		assert.Equal(t, "msg", m["msg"])
+	}
+
+	assert.PanicsWithValue(t, "panic message", func() { log.Panic("panic message") })
+	assert.Equal(t, "panic", Map(buf)["level"])
+	assert.PanicsWithValue(t, "panic 2", func() { log.Panicf("panic %d", 2) })
+	assert.Equal(t, "panic 2", Map(buf)["msg"])
+
+	// Filtering follows severity, not the values of the levels added after fatal
+	log = log.WithLevel(LevelDebug)
+	log.Trace("dropped")
+	assert.Equal(t, 0, buf.Len())
+	log.WithLevel(LevelWarn).Notice("dropped")
+	assert.Equal(t, 0, buf.Len())
+	log.WithLevel(LevelCritical).Error("dropped")
+	assert.Equal(t, 0, buf.Len())
+	assert.Panics(t, func() { log.WithLevel(LevelCritical).Fatal("kept") })
+	assert.Equal(t, "kept", Map(buf)["msg"])
+	assert.False(t, log.WithLevel(LevelEmergency).IsLevelEnabled(LevelPanic))
+	assert.True(t, log.WithLevel(LevelAlert).IsLevelEnabled(LevelPanic))
+}
+
+func TestLevel_Compare(t *testing.T) {
+	ladder := []Level{LevelTrace, LevelDebug, LevelInfo, LevelNotice, LevelWarn, LevelError,
+		LevelCritical, LevelAlert, LevelFatal, LevelPanic, LevelEmergency, Level(9), LevelPrint}
+	for i, level := range ladder {
+		assert.Equal(t, 0, level.Compare(level), level.String())
+		for _, above := range ladder[i+1:] {
+			assert.Equal(t, -1, level.Compare(above), "%s < %s", level, above)
+			assert.Equal(t, 1, above.Compare(level), "%s > %s", above, level)
+			assert.True(t, above.AtLeast(level))
+			assert.False(t, level.AtLeast(above))
+		}
+	}
+}
+
 func TestLogger_Concurrent_Logging(t *testing.T) {
 	// The logger now synchronizes writes to prevent concurrent access issues
 	// This test verifies that concurrent logging works safely with bytes.Buffer
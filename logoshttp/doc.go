@@ -0,0 +1,2 @@
+// Package logoshttp provides net/http integration for logos loggers.
+package logoshttp
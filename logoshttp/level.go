@@ -0,0 +1,116 @@
+package logoshttp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goodblaster/logos"
+)
+
+// Defaults for LevelOverrideOptions.
+const (
+	DefaultLevelHeader = "X-Log-Level"
+	DefaultLevelParam  = "log_level"
+	DefaultLevelMaxTTL = 24 * time.Hour
+)
+
+// LevelOverrideOptions configures LevelOverride.
+type LevelOverrideOptions struct {
+	Secret     []byte           // HMAC key used to sign tokens. Required.
+	Header     string           // Request header carrying a token. Defaults to DefaultLevelHeader.
+	QueryParam string           // Query parameter carrying a token. Defaults to DefaultLevelParam; "-" disables it.
+	MaxTTL     time.Duration    // Tokens expiring further in the future are rejected. Defaults to DefaultLevelMaxTTL.
+	Now        func() time.Time // Optional: clock used to check expiry. Defaults to time.Now.
+}
+
+// SignLevelOverride returns a token that makes LevelOverride log requests carrying it at
+// level until expires. The token has the form "<level>.<unix expiry>.<signature>".
+func SignLevelOverride(secret []byte, level logos.Level, expires time.Time) string {
+	payload := level.String() + "." + strconv.FormatInt(expires.Unix(), 10)
+	return payload + "." + levelSignature(secret, payload)
+}
+
+// LevelOverride returns middleware that stores a level override in the request context
+// (see logos.WithLevelOverride) when the request carries a valid token made by
+// SignLevelOverride, in the header or the query parameter. Requests with a missing,
+// expired or forged token are served unchanged.
+func LevelOverride(opts LevelOverrideOptions) func(http.Handler) http.Handler {
+	opts = opts.withDefaults()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if level, ok := opts.level(r); ok {
+				r = r.WithContext(logos.WithLevelOverride(r.Context(), level))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func (opts LevelOverrideOptions) withDefaults() LevelOverrideOptions {
+	if opts.Header == "" {
+		opts.Header = DefaultLevelHeader
+	}
+	if opts.QueryParam == "" {
+		opts.QueryParam = DefaultLevelParam
+	}
+	if opts.MaxTTL <= 0 {
+		opts.MaxTTL = DefaultLevelMaxTTL
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return opts
+}
+
+// level returns the level of the request's token if it is valid.
+func (opts LevelOverrideOptions) level(r *http.Request) (logos.Level, bool) {
+	if len(opts.Secret) == 0 {
+		return 0, false
+	}
+
+	token := r.Header.Get(opts.Header)
+	if token == "" && opts.QueryParam != "-" {
+		token = r.URL.Query().Get(opts.QueryParam)
+	}
+	if token == "" {
+		return 0, false
+	}
+
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return 0, false
+	}
+	payload, signature := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(signature), []byte(levelSignature(opts.Secret, payload))) {
+		return 0, false
+	}
+
+	// The level name may itself contain dots, so the expiry is taken from the end.
+	i = strings.LastIndexByte(payload, '.')
+	if i < 0 {
+		return 0, false
+	}
+	name, expiry := payload[:i], payload[i+1:]
+	unix, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	now := opts.Now()
+	if expires := time.Unix(unix, 0); !now.Before(expires) || expires.Sub(now) > opts.MaxTTL {
+		return 0, false
+	}
+
+	level, err := logos.ParseLevel(name)
+	return level, err == nil
+}
+
+func levelSignature(secret []byte, payload string) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
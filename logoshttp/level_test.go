@@ -0,0 +1,99 @@
+package logoshttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/goodblaster/logos"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLevelOverride(t *testing.T) {
+	secret := []byte("s3cret")
+	now := time.Unix(1_700_000_000, 0)
+
+	var got *logos.Level
+	handler := LevelOverride(LevelOverrideOptions{
+		Secret: secret,
+		MaxTTL: time.Hour,
+		Now:    func() time.Time { return now },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = nil
+		if level, ok := logos.LevelOverride(r.Context()); ok {
+			got = &level
+		}
+	}))
+
+	valid := SignLevelOverride(secret, logos.LevelDebug, now.Add(30*time.Minute))
+
+	tests := []struct {
+		name   string
+		header string
+		query  string
+		want   *logos.Level
+	}{
+		{name: "none"},
+		{name: "header", header: valid, want: levelPtr(logos.LevelDebug)},
+		{name: "query", query: valid, want: levelPtr(logos.LevelDebug)},
+		{name: "expired", header: SignLevelOverride(secret, logos.LevelDebug, now.Add(-time.Second))},
+		{name: "too long", header: SignLevelOverride(secret, logos.LevelDebug, now.Add(2*time.Hour))},
+		{name: "wrong secret", header: SignLevelOverride([]byte("other"), logos.LevelDebug, now.Add(time.Minute))},
+		{name: "tampered level", header: "info" + valid[len("debug"):]},
+		{name: "garbage", header: "debug"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/"
+			if tt.query != "" {
+				target += "?" + url.Values{DefaultLevelParam: {tt.query}}.Encode()
+			}
+			r := httptest.NewRequest(http.MethodGet, target, nil)
+			if tt.header != "" {
+				r.Header.Set(DefaultLevelHeader, tt.header)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), r)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLevelOverride_QueryDisabled(t *testing.T) {
+	secret := []byte("s3cret")
+	called := false
+	handler := LevelOverride(LevelOverrideOptions{Secret: secret, QueryParam: "-"})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			_, ok := logos.LevelOverride(r.Context())
+			assert.False(t, ok)
+		}))
+
+	token := SignLevelOverride(secret, logos.LevelDebug, time.Now().Add(time.Minute))
+	r := httptest.NewRequest(http.MethodGet, "/?"+url.Values{DefaultLevelParam: {token}}.Encode(), nil)
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	assert.True(t, called)
+}
+
+func levelPtr(level logos.Level) *logos.Level {
+	return &level
+}
+
+func TestLevelOverride_DottedLevelName(t *testing.T) {
+	const levelAudit = logos.LevelPanic + 3
+	assert.NoError(t, logos.RegisterLevel(levelAudit, logos.LevelSpec{Name: "app.audit"}))
+	t.Cleanup(func() { logos.UnregisterLevel(levelAudit) })
+
+	secret := []byte("s3cret")
+	var got logos.Level
+	handler := LevelOverride(LevelOverrideOptions{Secret: secret})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got, _ = logos.LevelOverride(r.Context())
+		}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(DefaultLevelHeader, SignLevelOverride(secret, levelAudit, time.Now().Add(time.Minute)))
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	assert.Equal(t, levelAudit, got)
+}
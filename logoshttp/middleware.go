@@ -0,0 +1,331 @@
+package logoshttp
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/goodblaster/errors"
+	"github.com/goodblaster/logos"
+)
+
+// DefaultRequestIDHeader is the header used to propagate request IDs.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// Field keys added by Middleware.
+const (
+	FieldRequestID = "request_id"
+	FieldRoute     = "route"
+	FieldPanic     = "panic"
+	FieldStack     = "stack"
+)
+
+// maxRequestIDLength bounds incoming request IDs, which are logged verbatim.
+const maxRequestIDLength = 128
+
+type contextKey string
+
+// ctxKeyRequestID is the key used to store the request ID in context.Context.
+const ctxKeyRequestID contextKey = "logoshttp.request_id"
+
+// Options configures Middleware. The zero value is ready to use.
+type Options struct {
+	// Logger returns the base logger for a request. Defaults to logos.FromContext(r.Context()).
+	Logger func(r *http.Request) logos.Logger
+	// RequestIDHeader is read to propagate an incoming request ID and set on the response.
+	// Defaults to DefaultRequestIDHeader.
+	RequestIDHeader string
+	// GenerateRequestID creates IDs for requests without one. Defaults to 16 random hex bytes.
+	GenerateRequestID func() string
+	// Route returns the route pattern that matched the request, such as "/users/{id}".
+	// The route field is omitted if nil or if it returns an empty string.
+	Route func(r *http.Request) string
+	// Level chooses the access log level from the status code. Defaults to StatusLevel.
+	Level func(status int) logos.Level
+	// CombinedLogFormat writes the access log entry as an Apache Combined Log Format line,
+	// without fields, instead of a message with an HTTPRequest field.
+	CombinedLogFormat bool
+	// RedactQueryParams are the query parameters masked in the logged URL, as by Transport.
+	// Defaults to DefaultRedactQueryParams.
+	RedactQueryParams []string
+	// LevelOverride, if set, enables per-request level overrides (see LevelOverride).
+	// The request-scoped logger and the access log entry use the overridden level.
+	LevelOverride *LevelOverrideOptions
+	// TraceContext reads the W3C traceparent and tracestate headers and stores a child span
+	// of the incoming trace, or a new unsampled trace if there is none, in the context with
+	// logos.WithTraceContext. Entries logged for the request then carry trace_id and span_id,
+	// and Transport propagates the trace to outbound requests.
+	TraceContext bool
+}
+
+// StatusLevel returns LevelError for 5xx statuses, LevelWarn for 4xx and LevelInfo otherwise.
+func StatusLevel(status int) logos.Level {
+	switch {
+	case status >= 500:
+		return logos.LevelError
+	case status >= 400:
+		return logos.LevelWarn
+	default:
+		return logos.LevelInfo
+	}
+}
+
+// Middleware returns net/http middleware that, for each request:
+//
+//   - propagates the request ID from the request header, or generates one, and sets it on
+//     the response;
+//   - stores a request-scoped logger carrying the request ID in the context with
+//     logos.WithLogger, so handlers can use logos.FromContext(r.Context());
+//   - with Options.TraceContext, continues the caller's W3C trace or starts a new one;
+//   - recovers panics, logging them with their stack and answering 500 if nothing was written,
+//     and marks the access log entry with the panic;
+//   - writes an access log entry once the handler returns, with its level chosen by status.
+//
+// The access log entry carries a logos.HTTPRequest under logos.FieldHTTPRequest (method, URL,
+// status, sizes, latency, remote IP, user agent, referer and protocol) and the route if known.
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	opts = opts.withDefaults()
+
+	return func(next http.Handler) http.Handler {
+		handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get(opts.RequestIDHeader)
+			if !validRequestID(requestID) {
+				requestID = opts.GenerateRequestID()
+			}
+			w.Header().Set(opts.RequestIDHeader, requestID)
+
+			logger := opts.Logger(r).With(FieldRequestID, requestID)
+			ctx := WithRequestID(r.Context(), requestID)
+			ctx = logos.WithLogger(ctx, logger)
+			if opts.TraceContext {
+				ctx = logos.WithTraceContext(ctx, incomingTrace(r))
+			}
+			r = r.WithContext(ctx)
+
+			rw := &responseWriter{ResponseWriter: w}
+			defer func() {
+				logger := logos.FromContext(r.Context())
+				if p := recover(); p != nil {
+					if p == http.ErrAbortHandler {
+						panic(p)
+					}
+					logger = logger.With(FieldPanic, fmt.Sprint(p))
+					logger.With(FieldStack, string(debug.Stack())).Error("panic serving request")
+					// A status already written is the one the client got, so it is kept
+					if !rw.wroteHeader {
+						http.Error(rw, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+					}
+				}
+				opts.accessLog(logger, r, rw, start)
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+
+		if opts.LevelOverride != nil {
+			return LevelOverride(*opts.LevelOverride)(handler)
+		}
+		return handler
+	}
+}
+
+func (opts Options) withDefaults() Options {
+	if opts.Logger == nil {
+		opts.Logger = func(r *http.Request) logos.Logger {
+			return logos.FromContext(r.Context())
+		}
+	}
+	if opts.RequestIDHeader == "" {
+		opts.RequestIDHeader = DefaultRequestIDHeader
+	}
+	if opts.GenerateRequestID == nil {
+		opts.GenerateRequestID = NewRequestID
+	}
+	if opts.Level == nil {
+		opts.Level = StatusLevel
+	}
+	return opts
+}
+
+// accessLog writes the access log entry for a completed request.
+func (opts Options) accessLog(logger logos.Logger, r *http.Request, rw *responseWriter, start time.Time) {
+	status := rw.statusCode()
+	level := opts.Level(status)
+	redacted := *r.URL
+	redacted.RawQuery = redactQuery(redacted.RawQuery, opts.RedactQueryParams)
+	uri := redacted.RequestURI()
+
+	if opts.CombinedLogFormat {
+		logger.Log(level, combinedLogLine(r, uri, status, rw.bytes, start))
+		return
+	}
+
+	req := logos.HTTPRequest{
+		Method:       r.Method,
+		URL:          uri,
+		Status:       status,
+		RequestSize:  r.ContentLength,
+		ResponseSize: rw.bytes,
+		UserAgent:    r.UserAgent(),
+		RemoteIP:     remoteIP(r),
+		Referer:      r.Referer(),
+		Protocol:     r.Proto,
+		Latency:      time.Since(start),
+	}
+	if req.RequestSize < 0 {
+		req.RequestSize = 0
+	}
+
+	logger = logger.With(logos.FieldHTTPRequest, req)
+	if opts.Route != nil {
+		if route := opts.Route(r); route != "" {
+			logger = logger.With(FieldRoute, route)
+		}
+	}
+	logger.Logf(level, "%s %s %d", r.Method, r.URL.Path, status)
+}
+
+// incomingTrace returns a child span of the trace in the request headers, or a new trace
+// if they carry none or an invalid one.
+func incomingTrace(r *http.Request) logos.TraceContext {
+	tc, err := logos.ParseTraceContext(r.Header.Get(logos.TraceParentHeader), r.Header.Get(logos.TraceStateHeader))
+	if err != nil {
+		return logos.NewTraceContext(false)
+	}
+	return tc.NewSpan()
+}
+
+// combinedLogLine renders a request for uri in the Apache Combined Log Format:
+//
+//	host ident user [time] "request line" status bytes "referer" "user agent"
+func combinedLogLine(r *http.Request, uri string, status int, bytes int64, start time.Time) string {
+	user := "-"
+	if r.URL.User != nil && r.URL.User.Username() != "" {
+		user = r.URL.User.Username()
+	} else if name, _, ok := r.BasicAuth(); ok && name != "" {
+		user = name
+	}
+
+	size := "-"
+	if bytes > 0 {
+		size = strconv.FormatInt(bytes, 10)
+	}
+
+	return fmt.Sprintf("%s - %s [%s] %q %d %s %q %q",
+		remoteIP(r),
+		user,
+		start.Format("02/Jan/2006:15:04:05 -0700"),
+		r.Method+" "+uri+" "+r.Proto,
+		status,
+		size,
+		r.Referer(),
+		r.UserAgent())
+}
+
+// WithRequestID returns a new context carrying the request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKeyRequestID, id)
+}
+
+// RequestID returns the request ID stored in the context by Middleware, or "" if none.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(ctxKeyRequestID).(string)
+	return id
+}
+
+// NewRequestID returns a random 32-character hex request ID.
+func NewRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
+
+// validRequestID reports whether an incoming request ID is safe to propagate and log.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if c := id[i]; c <= ' ' || c > '~' || c == '"' {
+			return false
+		}
+	}
+	return true
+}
+
+// remoteIP returns the IP address of the client connection.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// responseWriter records the status code and body size written by a handler.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// statusCode returns the status sent to the client, which is 200 if the handler wrote nothing.
+func (w *responseWriter) statusCode() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Flush implements http.Flusher when the underlying writer does.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		if !w.wroteHeader {
+			w.WriteHeader(http.StatusOK)
+		}
+		f.Flush()
+	}
+}
+
+// Hijack implements http.Hijacker when the underlying writer does.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("logoshttp: underlying ResponseWriter does not implement http.Hijacker")
+	}
+	return h.Hijack()
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
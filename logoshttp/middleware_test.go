@@ -0,0 +1,231 @@
+package logoshttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goodblaster/logos"
+	"github.com/stretchr/testify/assert"
+)
+
+// lines decodes each JSON log line written to buf.
+func lines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(line), &m), line)
+		out = append(out, m)
+	}
+	return out
+}
+
+func fields(m map[string]any) map[string]any {
+	f, _ := m["fields"].(map[string]any)
+	return f
+}
+
+func newTestMiddleware(buf *bytes.Buffer, opts Options) func(http.Handler) http.Handler {
+	logger := logos.NewLogger(logos.LevelDebug, logos.JSONFormatter(), buf)
+	opts.Logger = func(r *http.Request) logos.Logger { return logger }
+	return Middleware(opts)
+}
+
+func TestMiddleware_AccessLog(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var handlerRequestID string
+	handler := newTestMiddleware(buf, Options{
+		Route: func(r *http.Request) string { return "/users/{id}" },
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handlerRequestID = RequestID(r.Context())
+		logos.FromContext(r.Context()).Info("handling")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/users/7?x=1&token=abc", strings.NewReader("body"))
+	r.Header.Set("User-Agent", "test-agent")
+	r.Header.Set(DefaultRequestIDHeader, "req-123")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+
+	assert.Equal(t, "req-123", rec.Header().Get(DefaultRequestIDHeader))
+	assert.Equal(t, "req-123", handlerRequestID)
+
+	entries := lines(t, buf)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "handling", entries[0]["msg"])
+	assert.Equal(t, "req-123", fields(entries[0])[FieldRequestID])
+
+	access := entries[1]
+	assert.Equal(t, "info", access["level"])
+	assert.Equal(t, "POST /users/7 201", access["msg"])
+	assert.Equal(t, "req-123", fields(access)[FieldRequestID])
+	assert.Equal(t, "/users/{id}", fields(access)[FieldRoute])
+
+	req := fields(access)[logos.FieldHTTPRequest].(map[string]any)
+	assert.Equal(t, "POST", req["method"])
+	assert.Equal(t, "/users/7?token=[REDACTED]&x=1", req["url"])
+	assert.EqualValues(t, 201, req["status"])
+	assert.EqualValues(t, 4, req["request_size"])
+	assert.EqualValues(t, 5, req["response_size"])
+	assert.Equal(t, "test-agent", req["user_agent"])
+	assert.Equal(t, "192.0.2.1", req["remote_ip"])
+	assert.Equal(t, "HTTP/1.1", req["protocol"])
+	assert.Contains(t, req, "latency")
+}
+
+func TestMiddleware_RequestIDGenerated(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := newTestMiddleware(buf, Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for _, incoming := range []string{"", "bad id with spaces", strings.Repeat("x", 200)} {
+		buf.Reset()
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		if incoming != "" {
+			r.Header.Set(DefaultRequestIDHeader, incoming)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, r)
+
+		id := rec.Header().Get(DefaultRequestIDHeader)
+		assert.Len(t, id, 32)
+		assert.Equal(t, id, fields(lines(t, buf)[0])[FieldRequestID])
+	}
+}
+
+func TestMiddleware_StatusLevels(t *testing.T) {
+	tests := map[int]string{200: "info", 302: "info", 404: "warn", 503: "error"}
+	for status, level := range tests {
+		buf := &bytes.Buffer{}
+		handler := newTestMiddleware(buf, Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, level, lines(t, buf)[0]["level"], status)
+	}
+}
+
+func TestMiddleware_Panic(t *testing.T) {
+	buf := &bytes.Buffer{}
+	handler := newTestMiddleware(buf, Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	entries := lines(t, buf)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "error", entries[0]["level"])
+	assert.Equal(t, "boom", fields(entries[0])[FieldPanic])
+	assert.Contains(t, fields(entries[0])[FieldStack], "middleware_test.go")
+	assert.Equal(t, "error", entries[1]["level"])
+	assert.EqualValues(t, 500, fields(entries[1])[logos.FieldHTTPRequest].(map[string]any)["status"])
+	assert.Equal(t, "boom", fields(entries[1])[FieldPanic])
+
+	// After the headers were written, the access log keeps the status the client got
+	buf.Reset()
+	written := newTestMiddleware(buf, Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("partial"))
+		panic("late boom")
+	}))
+	rec = httptest.NewRecorder()
+	written.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	entries = lines(t, buf)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "info", entries[1]["level"])
+	assert.Equal(t, "GET / 200", entries[1]["msg"])
+	assert.EqualValues(t, 200, fields(entries[1])[logos.FieldHTTPRequest].(map[string]any)["status"])
+	assert.Equal(t, "late boom", fields(entries[1])[FieldPanic])
+
+	abort := newTestMiddleware(buf, Options{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		abort.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+}
+
+func TestMiddleware_CombinedLogFormat(t *testing.T) {
+	buf := &bytes.Buffer{}
+	opts := Options{CombinedLogFormat: true, RedactQueryParams: []string{"email"}}
+	handler := newTestMiddleware(buf, opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/index.html?q=1&email=a%40b.c", nil)
+	r.SetBasicAuth("frank", "secret")
+	r.Header.Set("Referer", "http://example.com/")
+	r.Header.Set("User-Agent", "Mozilla/5.0")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	entry := lines(t, buf)[0]
+	msg := entry["msg"].(string)
+	assert.Regexp(t, `^192\.0\.2\.1 - frank \[\d{2}/\w{3}/\d{4}:\d{2}:\d{2}:\d{2} [+-]\d{4}\] "GET /index.html\?email=\[REDACTED\]&q=1 HTTP/1.1" 200 5 "http://example.com/" "Mozilla/5.0"$`, msg)
+	assert.Nil(t, fields(entry)[logos.FieldHTTPRequest])
+}
+
+func TestMiddleware_LevelOverride(t *testing.T) {
+	secret := []byte("s3cret")
+	buf := &bytes.Buffer{}
+	logger := logos.NewLogger(logos.LevelWarn, logos.JSONFormatter(), buf)
+	handler := Middleware(Options{
+		Logger:        func(r *http.Request) logos.Logger { return logger },
+		LevelOverride: &LevelOverrideOptions{Secret: secret},
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logos.FromContext(r.Context()).Debug("debugging")
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Empty(t, buf.String())
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(DefaultLevelHeader, SignLevelOverride(secret, logos.LevelDebug, time.Now().Add(time.Minute)))
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	entries := lines(t, buf)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "debugging", entries[0]["msg"])
+	assert.Equal(t, "GET / 200", entries[1]["msg"])
+}
+
+func TestMiddleware_TraceContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var tc logos.TraceContext
+	handler := newTestMiddleware(buf, Options{TraceContext: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tc, _ = logos.TraceContextFromContext(r.Context())
+		logos.FromContext(r.Context()).Info("handling")
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(logos.TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.Header.Set(logos.TraceStateHeader, "congo=t61rcWkgMzE")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceID)
+	assert.NotEqual(t, "00f067aa0ba902b7", tc.SpanID) // A child span of the caller's
+	assert.True(t, tc.Sampled())
+	assert.Equal(t, "congo=t61rcWkgMzE", tc.TraceState)
+	for _, entry := range lines(t, buf) {
+		assert.Equal(t, tc.TraceID, entry[logos.FieldTraceID])
+		assert.Equal(t, tc.SpanID, entry[logos.FieldSpanID])
+	}
+
+	// Requests without a valid traceparent start a new trace
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set(logos.TraceParentHeader, "garbage")
+	handler.ServeHTTP(httptest.NewRecorder(), r)
+	assert.True(t, tc.IsValid())
+	assert.NotEqual(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceID)
+	assert.False(t, tc.Sampled())
+}
@@ -0,0 +1,358 @@
+package logoshttp
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/goodblaster/logos"
+)
+
+// Field keys added by Transport.
+const (
+	FieldAttempt         = "attempt"
+	FieldRequestHeaders  = "request_headers"
+	FieldResponseHeaders = "response_headers"
+	FieldRequestBody     = "request_body"
+	FieldResponseBody    = "response_body"
+)
+
+// DefaultRedactHeaders are the headers masked by Transport when RedactHeaders is nil.
+var DefaultRedactHeaders = []string{
+	"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Auth-Token",
+}
+
+// DefaultRedactQueryParams are the query parameters masked by Transport and Middleware when
+// RedactQueryParams is nil.
+var DefaultRedactQueryParams = []string{
+	"access_token", "api_key", "apikey", "key", "password", "secret", "signature", "sig", "token",
+}
+
+// ctxKeyAttempt is the key used to store the retry attempt in context.Context.
+const ctxKeyAttempt contextKey = "logoshttp.attempt"
+
+// WithAttempt returns a new context recording the retry attempt of an outbound request,
+// starting at 1 for the first retry. Transport logs it as the attempt field.
+func WithAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, ctxKeyAttempt, attempt)
+}
+
+// Transport is an http.RoundTripper that logs each outbound request through the Logger
+// found in the request context (see logos.FromContext). The entry is written when the
+// response body is closed or fully read, so it includes the response size and full duration,
+// or as soon as the round trip fails.
+//
+// The request ID and the W3C trace context stored in the request context, if any, are
+// propagated in the request headers unless the caller already set them.
+type Transport struct {
+	Base              http.RoundTripper            // Optional: transport doing the work. Defaults to http.DefaultTransport.
+	RedactHeaders     []string                     // Optional: headers to mask. Defaults to DefaultRedactHeaders.
+	RedactQueryParams []string                     // Optional: query parameters to mask. Defaults to DefaultRedactQueryParams.
+	LogHeaders        bool                         // Log request and response headers, with RedactHeaders masked.
+	MaxBodySize       int                          // If positive, capture up to this many body bytes in a separate debug entry.
+	RequestIDHeader   string                       // Header carrying the request ID from the context. Defaults to DefaultRequestIDHeader.
+	Level             func(status int) logos.Level // Optional: entry level by status. Defaults to StatusLevel. Failures use LevelError.
+}
+
+// RoundTrip executes the request with the base transport and logs it.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx := req.Context()
+	logger := logos.FromContext(ctx)
+
+	header := t.RequestIDHeader
+	if header == "" {
+		header = DefaultRequestIDHeader
+	}
+	id := RequestID(ctx)
+	setID := id != "" && req.Header.Get(header) == ""
+	tc, setTrace := logos.TraceContextFromContext(ctx)
+	setTrace = setTrace && req.Header.Get(logos.TraceParentHeader) == ""
+	// Bodies are captured if the logger or any of its tees would write the debug entry
+	captureBodies := false
+	if t.MaxBodySize > 0 {
+		logger.LogIf(logos.LevelDebug, func() { captureBodies = true })
+	}
+
+	var requestBody *captureReader
+	if setID || setTrace || (captureBodies && req.Body != nil) {
+		// RoundTrippers must not modify the caller's request
+		req = req.Clone(ctx)
+		if setID {
+			req.Header.Set(header, id)
+		}
+		if setTrace {
+			req.Header.Set(logos.TraceParentHeader, tc.TraceParent())
+			if tc.TraceState != "" {
+				req.Header.Set(logos.TraceStateHeader, tc.TraceState)
+			}
+		}
+		if captureBodies && req.Body != nil && req.Body != http.NoBody {
+			requestBody = &captureReader{ReadCloser: req.Body, limit: t.MaxBodySize}
+			req.Body = requestBody
+		}
+	}
+
+	call := &outboundCall{
+		transport:   t,
+		logger:      logger,
+		req:         req,
+		start:       time.Now(),
+		requestBody: requestBody,
+	}
+	if attempt, ok := ctx.Value(ctxKeyAttempt).(int); ok {
+		call.attempt = attempt
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		call.log(nil, 0, nil, err)
+		return nil, err
+	}
+
+	body := &responseBody{ReadCloser: resp.Body, call: call, resp: resp}
+	if captureBodies {
+		body.capture = &captureReader{limit: t.MaxBodySize}
+	}
+	resp.Body = body
+	return resp, nil
+}
+
+// outboundCall holds what is needed to log a request once its response is consumed.
+type outboundCall struct {
+	transport   *Transport
+	logger      logos.Logger
+	req         *http.Request
+	start       time.Time
+	attempt     int
+	requestBody *captureReader
+}
+
+// log writes the entry for a finished call: either a response with the number of body bytes
+// received, or the error that ended the round trip. Captured bodies follow in a debug entry,
+// so that they stay out of entries written at other levels.
+func (c *outboundCall) log(resp *http.Response, size int64, responseBody *captureReader, err error) {
+	t := c.transport
+	req := logos.HTTPRequest{
+		Method:   c.req.Method,
+		URL:      t.redactURL(c.req.URL),
+		Protocol: c.req.Proto,
+		Latency:  time.Since(c.start),
+	}
+	if c.req.ContentLength > 0 {
+		req.RequestSize = c.req.ContentLength
+	}
+
+	logger := c.logger
+	if c.attempt > 0 {
+		logger = logger.With(FieldAttempt, c.attempt)
+	}
+	defer c.logBodies(logger, responseBody)
+	if t.LogHeaders {
+		logger = logger.With(FieldRequestHeaders, t.redactHeaders(c.req.Header))
+	}
+
+	if err != nil {
+		logger.With(logos.FieldHTTPRequest, req).WithError(err).
+			Errorf("%s %s%s failed", c.req.Method, c.req.URL.Host, c.req.URL.Path)
+		return
+	}
+
+	req.Status = resp.StatusCode
+	req.ResponseSize = size
+	if t.LogHeaders {
+		logger = logger.With(FieldResponseHeaders, t.redactHeaders(resp.Header))
+	}
+
+	levelOf := t.Level
+	if levelOf == nil {
+		levelOf = StatusLevel
+	}
+	level := levelOf(resp.StatusCode)
+	logger.With(logos.FieldHTTPRequest, req).
+		Logf(level, "%s %s%s %d", c.req.Method, c.req.URL.Host, c.req.URL.Path, resp.StatusCode)
+}
+
+// logBodies writes the captured bodies, if any, at debug level. They are passed as context
+// fields so that they also reach tee loggers, which may be the only ones writing debug entries.
+func (c *outboundCall) logBodies(logger logos.Logger, responseBody *captureReader) {
+	if c.requestBody == nil && responseBody == nil {
+		return
+	}
+	bodies := logos.Fields{}
+	if c.requestBody != nil {
+		bodies[FieldRequestBody] = c.requestBody.String()
+	}
+	if responseBody != nil {
+		bodies[FieldResponseBody] = responseBody.String()
+	}
+	ctx := logos.AddFields(c.req.Context(), bodies)
+	logger.DebugfContext(ctx, "%s %s%s bodies", c.req.Method, c.req.URL.Host, c.req.URL.Path)
+}
+
+// redactURL renders u with the configured query parameters masked and any password removed.
+func (t *Transport) redactURL(u *url.URL) string {
+	redacted := *u
+	if redacted.User != nil {
+		redacted.User = url.User(redacted.User.Username())
+	}
+	redacted.RawQuery = redactQuery(redacted.RawQuery, t.RedactQueryParams)
+	return redacted.String()
+}
+
+// redactQuery returns rawQuery with the values of params masked, or DefaultRedactQueryParams
+// if params is nil. The mask is written as is rather than URL-encoded. A query with nothing to
+// mask is returned unchanged.
+func redactQuery(rawQuery string, params []string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+	if params == nil {
+		params = DefaultRedactQueryParams
+	}
+	masked := func(key string) bool {
+		for _, param := range params {
+			if strings.EqualFold(key, param) {
+				return true
+			}
+		}
+		return false
+	}
+
+	query, _ := url.ParseQuery(rawQuery)
+	keys := make([]string, 0, len(query))
+	changed := false
+	for key := range query {
+		keys = append(keys, key)
+		changed = changed || masked(key)
+	}
+	if !changed {
+		return rawQuery
+	}
+
+	// As url.Values.Encode does, but leaving the mask readable
+	sort.Strings(keys)
+	var b strings.Builder
+	for _, key := range keys {
+		for _, value := range query[key] {
+			if b.Len() > 0 {
+				b.WriteByte('&')
+			}
+			b.WriteString(url.QueryEscape(key) + "=")
+			if masked(key) {
+				b.WriteString(logos.DefaultRedactMask)
+			} else {
+				b.WriteString(url.QueryEscape(value))
+			}
+		}
+	}
+	return b.String()
+}
+
+// redactHeaders returns a copy of h with the configured headers masked.
+func (t *Transport) redactHeaders(h http.Header) map[string][]string {
+	names := t.RedactHeaders
+	if names == nil {
+		names = DefaultRedactHeaders
+	}
+
+	out := make(map[string][]string, len(h))
+	for key, values := range h {
+		out[key] = values
+		for _, name := range names {
+			if http.CanonicalHeaderKey(name) == http.CanonicalHeaderKey(key) {
+				masked := make([]string, len(values))
+				for i := range masked {
+					masked[i] = logos.DefaultRedactMask
+				}
+				out[key] = masked
+				break
+			}
+		}
+	}
+	return out
+}
+
+// responseBody logs the call once the body is read to the end or closed.
+type responseBody struct {
+	io.ReadCloser
+	call    *outboundCall
+	resp    *http.Response
+	capture *captureReader
+	bytes   int64
+	once    sync.Once
+}
+
+func (b *responseBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.bytes += int64(n)
+	if b.capture != nil {
+		b.capture.record(p[:n])
+	}
+	if err == io.EOF {
+		b.finish()
+	}
+	return n, err
+}
+
+func (b *responseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.finish()
+	return err
+}
+
+// finish logs the call once, with the number of body bytes actually received.
+func (b *responseBody) finish() {
+	b.once.Do(func() {
+		b.call.log(b.resp, b.bytes, b.capture, nil)
+	})
+}
+
+// captureReader keeps the first limit bytes read through it. Request bodies may still be
+// read by the base transport while the entry is written, so access is synchronized.
+type captureReader struct {
+	io.ReadCloser
+	limit     int
+	mu        sync.Mutex
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (r *captureReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.record(p[:n])
+	return n, err
+}
+
+func (r *captureReader) record(p []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if room := r.limit - r.buf.Len(); room < len(p) {
+		if room > 0 {
+			r.buf.Write(p[:room])
+		}
+		r.truncated = len(p) > 0
+		return
+	}
+	r.buf.Write(p)
+}
+
+// String returns the captured bytes, marked if the body was longer than the limit.
+func (r *captureReader) String() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.truncated {
+		return r.buf.String() + "...(truncated)"
+	}
+	return r.buf.String()
+}
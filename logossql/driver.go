@@ -0,0 +1,337 @@
+package logossql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"github.com/goodblaster/errors"
+)
+
+var (
+	errNamedArgs = errors.New("logossql: driver does not support named arguments")
+	// The errors database/sql returns for options a driver without ConnBeginTx cannot honor
+	errIsolation = errors.New("sql: driver does not support non-default isolation level")
+	errReadOnly  = errors.New("sql: driver does not support read-only transactions")
+)
+
+// WrapDriver returns a driver that logs the operations of d. It also implements
+// driver.DriverContext, so sql.Open creates connections through WrapConnector.
+func WrapDriver(d driver.Driver, opts Options) driver.Driver {
+	return &loggingDriver{driver: d, log: newLogger(opts)}
+}
+
+// WrapConnector returns a connector whose connections log their operations.
+func WrapConnector(c driver.Connector, opts Options) driver.Connector {
+	return &loggingConnector{connector: c, log: newLogger(opts)}
+}
+
+type loggingDriver struct {
+	driver driver.Driver
+	log    *logger
+}
+
+func (d *loggingDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &loggingConn{conn: conn, log: d.log}, nil
+}
+
+func (d *loggingDriver) OpenConnector(name string) (driver.Connector, error) {
+	if dc, ok := d.driver.(driver.DriverContext); ok {
+		c, err := dc.OpenConnector(name)
+		if err != nil {
+			return nil, err
+		}
+		return &loggingConnector{connector: c, log: d.log, driver: d}, nil
+	}
+	return &loggingConnector{connector: dsnConnector{name: name, driver: d.driver}, log: d.log, driver: d}, nil
+}
+
+// dsnConnector adapts a driver without DriverContext to driver.Connector.
+type dsnConnector struct {
+	name   string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.name)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type loggingConnector struct {
+	connector driver.Connector
+	log       *logger
+	driver    driver.Driver // The wrapping driver, if opened through one
+}
+
+func (c *loggingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &loggingConn{conn: conn, log: c.log}, nil
+}
+
+func (c *loggingConnector) Driver() driver.Driver {
+	if c.driver != nil {
+		return c.driver
+	}
+	return &loggingDriver{driver: c.connector.Driver(), log: c.log}
+}
+
+// loggingConn wraps a connection. It implements every optional interface and falls back
+// to driver.ErrSkip or a neutral answer when the wrapped connection does not, which
+// database/sql treats like the interface being absent.
+type loggingConn struct {
+	conn driver.Conn
+	log  *logger
+}
+
+var (
+	_ driver.Conn               = (*loggingConn)(nil)
+	_ driver.ConnBeginTx        = (*loggingConn)(nil)
+	_ driver.ConnPrepareContext = (*loggingConn)(nil)
+	_ driver.ExecerContext      = (*loggingConn)(nil)
+	_ driver.QueryerContext     = (*loggingConn)(nil)
+	_ driver.Pinger             = (*loggingConn)(nil)
+	_ driver.SessionResetter    = (*loggingConn)(nil)
+	_ driver.Validator          = (*loggingConn)(nil)
+	_ driver.NamedValueChecker  = (*loggingConn)(nil)
+)
+
+func (c *loggingConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *loggingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	start := time.Now()
+	var stmt driver.Stmt
+	var err error
+	if pc, ok := c.conn.(driver.ConnPrepareContext); ok {
+		stmt, err = pc.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.conn.Prepare(query)
+	}
+	c.log.log(ctx, OpPrepare, query, nil, start, nil, err)
+	if err != nil {
+		return nil, err
+	}
+	return &loggingStmt{stmt: stmt, conn: c, query: query, log: c.log}, nil
+}
+
+func (c *loggingConn) Close() error {
+	return c.conn.Close()
+}
+
+func (c *loggingConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *loggingConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	start := time.Now()
+	var tx driver.Tx
+	var err error
+	if bc, ok := c.conn.(driver.ConnBeginTx); ok {
+		tx, err = bc.BeginTx(ctx, opts)
+	} else if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		err = errIsolation
+	} else if opts.ReadOnly {
+		err = errReadOnly
+	} else {
+		tx, err = c.conn.Begin()
+	}
+	c.log.log(ctx, OpBegin, "", nil, start, nil, err)
+	if err != nil {
+		return nil, err
+	}
+	return &loggingTx{tx: tx, ctx: ctx, log: c.log}, nil
+}
+
+func (c *loggingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ec, ok := c.conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	result, err := ec.ExecContext(ctx, query, args)
+	c.log.log(ctx, OpExec, query, args, start, result, err)
+	return result, err
+}
+
+func (c *loggingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	qc, ok := c.conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := qc.QueryContext(ctx, query, args)
+	c.log.log(ctx, OpQuery, query, args, start, nil, err)
+	return rows, err
+}
+
+func (c *loggingConn) Ping(ctx context.Context) error {
+	if p, ok := c.conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *loggingConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *loggingConn) IsValid() bool {
+	if v, ok := c.conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *loggingConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// loggingTx logs commits and rollbacks with the context the transaction began with.
+type loggingTx struct {
+	tx  driver.Tx
+	ctx context.Context
+	log *logger
+}
+
+func (t *loggingTx) Commit() error {
+	start := time.Now()
+	err := t.tx.Commit()
+	t.log.log(t.ctx, OpCommit, "", nil, start, nil, err)
+	return err
+}
+
+func (t *loggingTx) Rollback() error {
+	start := time.Now()
+	err := t.tx.Rollback()
+	t.log.log(t.ctx, OpRollback, "", nil, start, nil, err)
+	return err
+}
+
+// loggingStmt logs executions of a prepared statement.
+type loggingStmt struct {
+	stmt  driver.Stmt
+	conn  *loggingConn // Checks arguments when the statement does not
+	query string
+	log   *logger
+}
+
+var (
+	_ driver.StmtExecContext   = (*loggingStmt)(nil)
+	_ driver.StmtQueryContext  = (*loggingStmt)(nil)
+	_ driver.NamedValueChecker = (*loggingStmt)(nil)
+	_ driver.ColumnConverter   = (*loggingStmt)(nil)
+)
+
+func (s *loggingStmt) Close() error {
+	return s.stmt.Close()
+}
+
+func (s *loggingStmt) NumInput() int {
+	return s.stmt.NumInput()
+}
+
+func (s *loggingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+func (s *loggingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+func (s *loggingStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var result driver.Result
+	var err error
+	if ec, ok := s.stmt.(driver.StmtExecContext); ok {
+		result, err = ec.ExecContext(ctx, args)
+	} else if values, convErr := plainValues(args); convErr != nil {
+		err = convErr
+	} else {
+		result, err = s.stmt.Exec(values)
+	}
+	s.log.log(ctx, OpStmtExec, s.query, args, start, result, err)
+	return result, err
+}
+
+func (s *loggingStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if qc, ok := s.stmt.(driver.StmtQueryContext); ok {
+		rows, err = qc.QueryContext(ctx, args)
+	} else if values, convErr := plainValues(args); convErr != nil {
+		err = convErr
+	} else {
+		rows, err = s.stmt.Query(values)
+	}
+	s.log.log(ctx, OpStmtQuery, s.query, args, start, nil, err)
+	return rows, err
+}
+
+// CheckNamedValue checks arguments in the order database/sql uses for an unwrapped statement:
+// the statement's checker, then the connection's, then the statement's ColumnConverter, then
+// the default conversion. Since database/sql never consults the connection once the statement
+// has a checker, the connection's checker is called here.
+func (s *loggingStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	if err := s.conn.CheckNamedValue(nv); err != driver.ErrSkip {
+		return err
+	}
+	if _, ok := s.stmt.(driver.ColumnConverter); ok {
+		return driver.ErrSkip // database/sql converts with ColumnConverter
+	}
+	var err error
+	nv.Value, err = driver.DefaultParameterConverter.ConvertValue(nv.Value)
+	return err
+}
+
+// ColumnConverter forwards to the wrapped statement. database/sql only reaches it when
+// CheckNamedValue skips an argument, which it does only for statements implementing it.
+func (s *loggingStmt) ColumnConverter(idx int) driver.ValueConverter {
+	if cc, ok := s.stmt.(driver.ColumnConverter); ok {
+		return cc.ColumnConverter(idx)
+	}
+	return driver.DefaultParameterConverter
+}
+
+// namedValues converts positional values to named values.
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, len(args))
+	for i, value := range args {
+		named[i] = driver.NamedValue{Ordinal: i + 1, Value: value}
+	}
+	return named
+}
+
+// plainValues converts named values for drivers without context support, which cannot
+// receive named arguments.
+func plainValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errNamedArgs
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
@@ -0,0 +1,200 @@
+// Package logossql wraps database/sql drivers to log queries, statements and transactions
+// through logos loggers.
+package logossql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/goodblaster/logos"
+)
+
+// Field keys added to each entry.
+const (
+	FieldOperation    = "op"
+	FieldQuery        = "query"
+	FieldArgs         = "args"
+	FieldDuration     = "duration"
+	FieldRowsAffected = "rows_affected"
+)
+
+// Operations logged in the op field.
+const (
+	OpQuery     = "query"
+	OpExec      = "exec"
+	OpPrepare   = "prepare"
+	OpStmtQuery = "stmt_query"
+	OpStmtExec  = "stmt_exec"
+	OpBegin     = "begin"
+	OpCommit    = "commit"
+	OpRollback  = "rollback"
+)
+
+// DefaultMaxQueryLength is the number of query characters logged when Options.MaxQueryLength is zero.
+const DefaultMaxQueryLength = 1024
+
+// Options configures the logging of a wrapped driver. The zero value is ready to use.
+type Options struct {
+	// Logger returns the logger for an operation. Defaults to logos.FromContext.
+	// Operations without a context, such as Commit, use the context they were started with.
+	Logger func(ctx context.Context) logos.Logger
+	// Level is the level of successful operations. Defaults to LevelDebug.
+	Level *logos.Level
+	// SlowThreshold escalates operations taking at least this long to LevelWarn. Zero disables it.
+	SlowThreshold time.Duration
+	// MaxQueryLength truncates logged queries to this many characters. Defaults to
+	// DefaultMaxQueryLength; negative values disable truncation.
+	MaxQueryLength int
+	// LogArgs adds the query arguments to each entry.
+	LogArgs bool
+	// RedactArg reports whether an argument must be masked when LogArgs is set.
+	// See RedactArgNames and RedactAllArgs.
+	RedactArg func(query string, arg driver.NamedValue) bool
+}
+
+// RedactArgNames returns a RedactArg function masking the named arguments (sql.Named).
+func RedactArgNames(names ...string) func(query string, arg driver.NamedValue) bool {
+	set := make(map[string]bool, len(names))
+	for _, name := range names {
+		set[name] = true
+	}
+	return func(query string, arg driver.NamedValue) bool {
+		return set[arg.Name]
+	}
+}
+
+// RedactAllArgs is a RedactArg function masking every argument, which still logs their count.
+func RedactAllArgs(query string, arg driver.NamedValue) bool {
+	return true
+}
+
+// OpenDB opens a database using a wrapped connector, like sql.OpenDB.
+func OpenDB(c driver.Connector, opts Options) *sql.DB {
+	return sql.OpenDB(WrapConnector(c, opts))
+}
+
+// Register registers a wrapped driver with database/sql under name, for use with sql.Open.
+func Register(name string, d driver.Driver, opts Options) {
+	sql.Register(name, WrapDriver(d, opts))
+}
+
+// logger logs database operations according to the options.
+type logger struct {
+	opts Options
+}
+
+func newLogger(opts Options) *logger {
+	if opts.Logger == nil {
+		opts.Logger = logos.FromContext
+	}
+	if opts.Level == nil {
+		level := logos.LevelDebug
+		opts.Level = &level
+	}
+	if opts.MaxQueryLength == 0 {
+		opts.MaxQueryLength = DefaultMaxQueryLength
+	}
+	return &logger{opts: opts}
+}
+
+// log writes the entry for a finished operation. A nil result omits rows_affected, and
+// driver.ErrSkip, which asks database/sql to retry another way, is not logged.
+func (l *logger) log(ctx context.Context, op, query string, args []driver.NamedValue, start time.Time, result driver.Result, err error) {
+	if err == driver.ErrSkip {
+		return
+	}
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	duration := time.Since(start)
+	level := *l.opts.Level
+	switch {
+	case err != nil:
+		level = logos.LevelError
+	case l.opts.SlowThreshold > 0 && duration >= l.opts.SlowThreshold && !level.AtLeast(logos.LevelWarn):
+		level = logos.LevelWarn
+	}
+
+	log := l.opts.Logger(ctx)
+	log.LogIf(level, func() {
+		l.write(log, level, op, query, args, duration, result, err)
+	})
+}
+
+// write builds and writes the entry once the level is known to be enabled.
+func (l *logger) write(log logos.Logger, level logos.Level, op, query string, args []driver.NamedValue, duration time.Duration, result driver.Result, err error) {
+	fields := logos.Fields{
+		FieldOperation: op,
+		FieldDuration:  duration,
+	}
+	if query != "" {
+		fields[FieldQuery] = l.truncate(query)
+	}
+	if l.opts.LogArgs && len(args) > 0 {
+		fields[FieldArgs] = l.args(query, args)
+	}
+	if result != nil && err == nil {
+		if rows, rowsErr := result.RowsAffected(); rowsErr == nil {
+			fields[FieldRowsAffected] = rows
+		}
+	}
+
+	log = log.WithFields(fields)
+	if err != nil {
+		log = log.WithError(err)
+	}
+	log.Log(level, "sql "+op)
+}
+
+// truncate shortens a query to MaxQueryLength characters.
+func (l *logger) truncate(query string) string {
+	limit := l.opts.MaxQueryLength
+	if limit < 0 || utf8.RuneCountInString(query) <= limit {
+		return query
+	}
+	return string([]rune(query)[:limit]) + "...(truncated)"
+}
+
+// args returns the loggable argument values, with redacted ones masked. Named arguments
+// are logged as a map, positional ones as a list.
+func (l *logger) args(query string, args []driver.NamedValue) any {
+	value := func(arg driver.NamedValue) any {
+		if l.opts.RedactArg != nil && l.opts.RedactArg(query, arg) {
+			return logos.DefaultRedactMask
+		}
+		if b, ok := arg.Value.([]byte); ok {
+			return string(b)
+		}
+		return arg.Value
+	}
+
+	named := false
+	for _, arg := range args {
+		if arg.Name != "" {
+			named = true
+			break
+		}
+	}
+	if named {
+		m := make(map[string]any, len(args))
+		for _, arg := range args {
+			key := arg.Name
+			if key == "" {
+				key = "$" + strconv.Itoa(arg.Ordinal)
+			}
+			m[key] = value(arg)
+		}
+		return m
+	}
+
+	values := make([]any, len(args))
+	for i, arg := range args {
+		values[i] = value(arg)
+	}
+	return values
+}
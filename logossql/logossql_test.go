@@ -0,0 +1,403 @@
+package logossql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goodblaster/errors"
+	"github.com/goodblaster/logos"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDriver is an in-process driver. Queries containing "fail" return an error and
+// queries containing "slow" sleep for 20ms. Exec reports 3 affected rows.
+type fakeDriver struct {
+	legacy bool // Connections implement only driver.Conn
+}
+
+func (d fakeDriver) Open(name string) (driver.Conn, error) {
+	if d.legacy {
+		return &legacyConn{}, nil
+	}
+	return &fakeConn{}, nil
+}
+
+type fakeConn struct{}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	if strings.Contains(query, "fail") {
+		return nil, errors.New("syntax error")
+	}
+	return &fakeStmt{query: query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return fakeTx{}, nil }
+
+func (c *fakeConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	return run(query, driver.RowsAffected(3))
+}
+
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	return run(query, &fakeRows{})
+}
+
+// legacyConn has no context support, so database/sql goes through prepared statements.
+type legacyConn struct{}
+
+func (c *legacyConn) Prepare(query string) (driver.Stmt, error) { return &fakeStmt{query: query}, nil }
+func (c *legacyConn) Close() error                              { return nil }
+func (c *legacyConn) Begin() (driver.Tx, error)                 { return fakeTx{}, nil }
+
+type fakeStmt struct{ query string }
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return run(s.query, driver.RowsAffected(3))
+}
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	return run(s.query, &fakeRows{})
+}
+
+type fakeTx struct{}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return errors.New("already committed") }
+
+type fakeRows struct{ done bool }
+
+func (r *fakeRows) Columns() []string { return []string{"n"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = int64(1)
+	return nil
+}
+
+func run[T any](query string, value T) (T, error) {
+	if strings.Contains(query, "slow") {
+		time.Sleep(20 * time.Millisecond)
+	}
+	if strings.Contains(query, "fail") {
+		var zero T
+		return zero, errors.New("query failed")
+	}
+	return value, nil
+}
+
+// fakeConnector implements driver.Connector for WrapConnector.
+type fakeConnector struct{ driver fakeDriver }
+
+func (c fakeConnector) Connect(context.Context) (driver.Conn, error) { return c.driver.Open("") }
+func (c fakeConnector) Driver() driver.Driver                        { return c.driver }
+
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var m map[string]any
+		assert.NoError(t, json.Unmarshal([]byte(line), &m), line)
+		out = append(out, m)
+	}
+	buf.Reset()
+	return out
+}
+
+func fields(m map[string]any) map[string]any {
+	f, _ := m["fields"].(map[string]any)
+	return f
+}
+
+func testContext(buf *bytes.Buffer) context.Context {
+	return logos.WithLogger(context.Background(), logos.NewLogger(logos.LevelDebug, logos.JSONFormatter(), buf))
+}
+
+func TestWrapConnector_ExecAndQuery(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := testContext(buf)
+	db := OpenDB(fakeConnector{}, Options{LogArgs: true})
+	defer db.Close()
+
+	result, err := db.ExecContext(ctx, "UPDATE users SET name = ? WHERE id = ?", "alice", 7)
+	assert.NoError(t, err)
+	n, _ := result.RowsAffected()
+	assert.EqualValues(t, 3, n)
+
+	logged := entries(t, buf)
+	assert.Len(t, logged, 1)
+	assert.Equal(t, "debug", logged[0]["level"])
+	assert.Equal(t, "sql exec", logged[0]["msg"])
+	f := fields(logged[0])
+	assert.Equal(t, OpExec, f[FieldOperation])
+	assert.Equal(t, "UPDATE users SET name = ? WHERE id = ?", f[FieldQuery])
+	assert.Equal(t, []any{"alice", float64(7)}, f[FieldArgs])
+	assert.EqualValues(t, 3, f[FieldRowsAffected])
+	assert.Contains(t, f, FieldDuration)
+
+	rows, err := db.QueryContext(ctx, "SELECT n FROM t")
+	assert.NoError(t, err)
+	assert.True(t, rows.Next())
+	_ = rows.Close()
+
+	logged = entries(t, buf)
+	assert.Len(t, logged, 1)
+	assert.Equal(t, OpQuery, fields(logged[0])[FieldOperation])
+	assert.NotContains(t, fields(logged[0]), FieldRowsAffected)
+}
+
+func TestWrapConnector_ErrorsAndSlowQueries(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := testContext(buf)
+	db := OpenDB(fakeConnector{}, Options{SlowThreshold: 10 * time.Millisecond})
+	defer db.Close()
+
+	_, err := db.ExecContext(ctx, "DELETE fail")
+	assert.Error(t, err)
+	logged := entries(t, buf)
+	assert.Equal(t, "error", logged[0]["level"])
+	assert.Equal(t, []any{"query failed"}, logged[0]["error"])
+
+	_, err = db.ExecContext(ctx, "SELECT slow")
+	assert.NoError(t, err)
+	assert.Equal(t, "warn", entries(t, buf)[0]["level"])
+
+	_, err = db.ExecContext(ctx, "SELECT fast")
+	assert.NoError(t, err)
+	assert.Equal(t, "debug", entries(t, buf)[0]["level"])
+}
+
+func TestWrapConnector_Transactions(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := testContext(buf)
+	db := OpenDB(fakeConnector{}, Options{})
+	defer db.Close()
+
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	_, err = tx.ExecContext(ctx, "INSERT 1")
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	var ops []any
+	for _, entry := range entries(t, buf) {
+		ops = append(ops, fields(entry)[FieldOperation])
+	}
+	assert.Equal(t, []any{OpBegin, OpExec, OpCommit}, ops)
+
+	// Commit and rollback use the logger of the context the transaction began with.
+	tx, err = db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	assert.Error(t, tx.Rollback())
+	logged := entries(t, buf)
+	assert.Equal(t, OpRollback, fields(logged[1])[FieldOperation])
+	assert.Equal(t, "error", logged[1]["level"])
+}
+
+// txOptionsConn is a fakeConn that supports transaction options.
+type txOptionsConn struct {
+	fakeConn
+	opts *driver.TxOptions
+}
+
+func (c *txOptionsConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	*c.opts = opts
+	return fakeTx{}, nil
+}
+
+type txOptionsConnector struct{ opts *driver.TxOptions }
+
+func (c txOptionsConnector) Connect(context.Context) (driver.Conn, error) {
+	return &txOptionsConn{opts: c.opts}, nil
+}
+func (c txOptionsConnector) Driver() driver.Driver { return fakeDriver{} }
+
+func TestWrapConnector_TransactionOptions(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := testContext(buf)
+
+	// Options a driver cannot honor fail, as with an unwrapped driver
+	db := OpenDB(fakeConnector{}, Options{})
+	defer db.Close()
+	_, err := db.BeginTx(ctx, &sql.TxOptions{ReadOnly: true})
+	assert.EqualError(t, err, "sql: driver does not support read-only transactions")
+	_, err = db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable})
+	assert.EqualError(t, err, "sql: driver does not support non-default isolation level")
+
+	// and are passed on to a driver that supports them
+	var got driver.TxOptions
+	db = OpenDB(txOptionsConnector{opts: &got}, Options{})
+	defer db.Close()
+	tx, err := db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelSerializable, ReadOnly: true})
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+	assert.Equal(t, driver.TxOptions{Isolation: driver.IsolationLevel(sql.LevelSerializable), ReadOnly: true}, got)
+}
+
+func TestWrapDriver_PreparedStatements(t *testing.T) {
+	Register("logossql-fake-legacy", fakeDriver{legacy: true}, Options{LogArgs: true, RedactArg: RedactAllArgs})
+
+	db, err := sql.Open("logossql-fake-legacy", "")
+	assert.NoError(t, err)
+	defer db.Close()
+
+	buf := &bytes.Buffer{}
+	ctx := testContext(buf)
+
+	// Without context support, database/sql prepares a statement for each call.
+	_, err = db.ExecContext(ctx, "UPDATE users SET password = ?", "hunter2")
+	assert.NoError(t, err)
+
+	logged := entries(t, buf)
+	assert.Len(t, logged, 2)
+	assert.Equal(t, OpPrepare, fields(logged[0])[FieldOperation])
+	assert.Equal(t, OpStmtExec, fields(logged[1])[FieldOperation])
+	assert.Equal(t, []any{logos.DefaultRedactMask}, fields(logged[1])[FieldArgs])
+	assert.EqualValues(t, 3, fields(logged[1])[FieldRowsAffected])
+
+	stmt, err := db.PrepareContext(ctx, "SELECT n FROM t WHERE id = ?")
+	assert.NoError(t, err)
+	defer stmt.Close()
+	buf.Reset()
+
+	rows, err := stmt.QueryContext(ctx, 1)
+	assert.NoError(t, err)
+	_ = rows.Close()
+	logged = entries(t, buf)
+	assert.Equal(t, OpStmtQuery, fields(logged[len(logged)-1])[FieldOperation])
+}
+
+// point is an argument type only checkerConn and converterStmt accept.
+type point struct{ X, Y int }
+
+// checkerConn converts point arguments at the connection level, as drivers such as pgx do.
+// Statements record the arguments they receive. Queries containing "convert" prepare
+// statements with a ColumnConverter.
+type checkerConn struct {
+	legacyConn
+	got *[]driver.Value
+}
+
+func (c *checkerConn) Prepare(query string) (driver.Stmt, error) {
+	if strings.Contains(query, "convert") {
+		return &converterStmt{recordingStmt{got: c.got}}, nil
+	}
+	return &recordingStmt{got: c.got}, nil
+}
+
+func (c *checkerConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if p, ok := nv.Value.(point); ok {
+		nv.Value = fmt.Sprintf("(%d,%d)", p.X, p.Y)
+		return nil
+	}
+	return driver.ErrSkip
+}
+
+type recordingStmt struct{ got *[]driver.Value }
+
+func (s *recordingStmt) Close() error  { return nil }
+func (s *recordingStmt) NumInput() int { return -1 }
+func (s *recordingStmt) Exec(args []driver.Value) (driver.Result, error) {
+	*s.got = args
+	return driver.RowsAffected(1), nil
+}
+func (s *recordingStmt) Query(args []driver.Value) (driver.Rows, error) {
+	*s.got = args
+	return &fakeRows{}, nil
+}
+
+// converterStmt converts []int arguments to strings.
+type converterStmt struct{ recordingStmt }
+
+func (s *converterStmt) ColumnConverter(idx int) driver.ValueConverter { return intsConverter{} }
+
+type intsConverter struct{}
+
+func (intsConverter) ConvertValue(v any) (driver.Value, error) {
+	if ints, ok := v.([]int); ok {
+		return fmt.Sprint(ints), nil
+	}
+	return driver.DefaultParameterConverter.ConvertValue(v)
+}
+
+type checkerConnector struct{ got *[]driver.Value }
+
+func (c checkerConnector) Connect(context.Context) (driver.Conn, error) {
+	return &checkerConn{got: c.got}, nil
+}
+func (c checkerConnector) Driver() driver.Driver { return fakeDriver{} }
+
+func TestWrapConnector_PreparedArgumentChecks(t *testing.T) {
+	var got []driver.Value
+	db := OpenDB(checkerConnector{got: &got}, Options{})
+	defer db.Close()
+	ctx := testContext(&bytes.Buffer{})
+
+	stmt, err := db.PrepareContext(ctx, "INSERT INTO shapes VALUES (?, ?)")
+	assert.NoError(t, err)
+	_, err = stmt.ExecContext(ctx, point{1, 2}, int32(7))
+	assert.NoError(t, err)
+	assert.Equal(t, []driver.Value{"(1,2)", int64(7)}, got)
+	_, err = stmt.ExecContext(ctx, []int{1})
+	assert.Error(t, err)
+	_ = stmt.Close()
+
+	stmt, err = db.PrepareContext(ctx, "INSERT INTO shapes VALUES (?, ?) -- convert")
+	assert.NoError(t, err)
+	defer stmt.Close()
+	_, err = stmt.ExecContext(ctx, point{3, 4}, []int{5, 6})
+	assert.NoError(t, err)
+	assert.Equal(t, []driver.Value{"(3,4)", "[5 6]"}, got)
+}
+
+func TestOptions_ArgsAndTruncation(t *testing.T) {
+	buf := &bytes.Buffer{}
+	ctx := testContext(buf)
+	level := logos.LevelInfo
+	db := OpenDB(fakeConnector{}, Options{
+		Level:          &level,
+		LogArgs:        true,
+		RedactArg:      RedactArgNames("password"),
+		MaxQueryLength: 10,
+	})
+	defer db.Close()
+
+	_, err := db.ExecContext(ctx, "UPDATE users SET password = :password WHERE name = :name",
+		sql.Named("password", "hunter2"), sql.Named("name", "alice"))
+	assert.NoError(t, err)
+
+	logged := entries(t, buf)
+	assert.Equal(t, "info", logged[0]["level"])
+	f := fields(logged[0])
+	assert.Equal(t, "UPDATE use...(truncated)", f[FieldQuery])
+	assert.Equal(t, map[string]any{"password": logos.DefaultRedactMask, "name": "alice"}, f[FieldArgs])
+}
+
+func TestLogger_FromContext(t *testing.T) {
+	buf := &bytes.Buffer{}
+	db := OpenDB(fakeConnector{}, Options{})
+	defer db.Close()
+
+	// Without a logger in the context, the default logger is used.
+	logos.SetDefaultLogger(logos.NewLogger(logos.LevelDebug, logos.JSONFormatter(), buf))
+	_, err := db.ExecContext(context.Background(), "SELECT 1")
+	assert.NoError(t, err)
+	assert.Equal(t, "sql exec", entries(t, buf)[0]["msg"])
+
+	logos.SetDefaultLogger(logos.NewLogger(logos.LevelInfo, logos.JSONFormatter(), buf))
+	_, err = db.ExecContext(context.Background(), "SELECT 1")
+	assert.NoError(t, err)
+	assert.Empty(t, buf.String())
+}
@@ -0,0 +1,154 @@
+package logostest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goodblaster/errors"
+	"github.com/goodblaster/logos"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeT records failures instead of failing the real test.
+type fakeT struct {
+	testing.TB
+	mu       sync.Mutex
+	logs     []string
+	errors   []string
+	failed   bool
+	cleanups []func()
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Log(args ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.logs = append(t.logs, fmt.Sprint(args...))
+}
+
+func (t *fakeT) Errorf(format string, args ...any) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+	t.failed = true
+}
+
+func (t *fakeT) FailNow() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.failed = true
+}
+
+func (t *fakeT) Cleanup(f func()) {
+	t.cleanups = append(t.cleanups, f)
+}
+
+func (t *fakeT) finish() {
+	for _, f := range t.cleanups {
+		f()
+	}
+}
+
+func TestRecorder(t *testing.T) {
+	rec := NewRecorder()
+	log := rec.Logger(logos.LevelInfo).WithGroup("http").With("method", "GET")
+
+	log.Debug("hidden")
+	log.With("status", int64(200)).WithError(errors.New("boom")).Info("request served")
+	log.Named("db").Warnf("slow query %d", 3)
+
+	entries := rec.Entries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, logos.LevelInfo, entries[0].Level)
+	assert.Equal(t, "request served", entries[0].Msg)
+	assert.EqualError(t, entries[0].Error, "boom")
+	assert.False(t, entries[0].Time.IsZero())
+	method, ok := entries[0].Field("http.method")
+	assert.True(t, ok)
+	assert.Equal(t, "GET", method)
+	assert.Equal(t, "db", entries[1].Name)
+
+	assert.True(t, rec.Logged(logos.LevelInfo, "served", logos.Fields{"http.status": 200}))
+	assert.False(t, rec.Logged(logos.LevelInfo, "served", logos.Fields{"http.status": 404}))
+	assert.False(t, rec.Logged(logos.LevelError, "served"))
+	assert.Len(t, rec.Find(logos.LevelWarn, "slow"), 1)
+
+	rec.Reset()
+	assert.Equal(t, 0, rec.Len())
+}
+
+func TestTestLogger_WritesThroughLog(t *testing.T) {
+	ft := &fakeT{}
+	log := NewTestLogger(ft)
+	log.With("user", "alice").Info("hello")
+	assert.Len(t, ft.logs, 1)
+	assert.Contains(t, ft.logs[0], `user="alice"`)
+	assert.False(t, strings.HasSuffix(ft.logs[0], "\n"))
+
+	// Output after the test has finished is dropped
+	ft.finish()
+	log.Info("late")
+	assert.Len(t, ft.logs, 1)
+}
+
+func TestTestLogger_Assertions(t *testing.T) {
+	ft := &fakeT{}
+	log := NewTestLogger(ft)
+	log.With("order_id", 42).Info("order placed")
+
+	log.RequireLogged(logos.LevelInfo, "placed", logos.Fields{"order_id": 42})
+	log.NoErrorsLogged()
+	assert.False(t, ft.failed)
+
+	// Trace entries are recorded too
+	log.Trace("entering placeOrder")
+	log.RequireLogged(logos.LevelTrace, "entering")
+	assert.False(t, ft.failed)
+
+	log.AssertLogged(logos.LevelInfo, "placed", logos.Fields{"order_id": 7})
+	assert.True(t, ft.failed)
+	assert.Contains(t, ft.errors[0], "order_id=7")
+	assert.Contains(t, ft.errors[0], `info "order placed" order_id=42`)
+
+	ft = &fakeT{}
+	log = NewTestLogger(ft)
+	log.Print("not an error")
+	log.Notice("config reloaded")
+	log.Error("disk full")
+	log.NoErrorsLogged()
+	assert.Len(t, ft.errors, 1)
+	assert.Contains(t, ft.errors[0], "disk full")
+	assert.NotContains(t, ft.errors[0], "not an error")
+	assert.NotContains(t, ft.errors[0], "config reloaded")
+}
+
+func TestTestLogger_FailOnLevel(t *testing.T) {
+	ft := &fakeT{}
+	log := NewTestLogger(ft)
+	log.FailOnLevel(logos.LevelWarn)
+
+	log.Info("fine")
+	assert.False(t, ft.failed)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		log.Named("worker").Warn("retrying")
+	}()
+	<-done
+	assert.True(t, ft.failed)
+	assert.Contains(t, ft.errors[0], "retrying")
+
+	// Notices rank below warnings, and critical entries above errors
+	ft = &fakeT{}
+	log = NewTestLogger(ft)
+	log.FailOnLevel(logos.LevelError)
+	log.Notice("config reloaded")
+	log.Warn("slow")
+	assert.False(t, ft.failed)
+	log.Critical("replica lost")
+	assert.True(t, ft.failed)
+}
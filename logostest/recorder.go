@@ -0,0 +1,183 @@
+// Package logostest helps test code that logs with logos. A Recorder captures structured
+// entries instead of formatted text, and a TestLogger writes through t.Log and adds
+// assertions on what was logged.
+package logostest
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/goodblaster/logos"
+)
+
+// Entry is a recorded log entry: the logos.Entry given to the formatter, after fields were
+// resolved and transformers applied, and the level it was logged at.
+type Entry struct {
+	logos.Entry
+	Level logos.Level
+}
+
+// Field returns the value of a field, looking inside groups for dotted keys such as
+// "http.method".
+func (e Entry) Field(key string) (any, bool) {
+	if value, ok := e.Fields[key]; ok {
+		return value, true
+	}
+
+	var current any = map[string]any(e.Fields)
+	for _, part := range strings.Split(key, ".") {
+		var m map[string]any
+		switch v := current.(type) {
+		case map[string]any:
+			m = v
+		case logos.Group:
+			m = v
+		default:
+			return nil, false
+		}
+		value, ok := m[part]
+		if !ok {
+			return nil, false
+		}
+		current = value
+	}
+	return current, true
+}
+
+// String renders the entry on one line for failure messages.
+func (e Entry) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %q", e.Level, e.Msg)
+	for _, key := range sortedKeys(e.Fields) {
+		fmt.Fprintf(&b, " %s=%v", key, e.Fields[key])
+	}
+	if e.Error != nil {
+		fmt.Fprintf(&b, " error=%q", e.Error.Error())
+	}
+	return b.String()
+}
+
+// Recorder is a logos.Formatter that records every entry it formats. Loggers derived from a
+// recording logger share its Recorder, so entries logged by code under test are captured
+// however it derives its loggers. A Recorder is safe for concurrent use.
+type Recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    logos.Formatter   // Optional: formatter producing the written text
+	onEntry func(entry Entry) // Optional: called for each recorded entry, outside the lock
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Logger returns a logger at the given level that records into r and writes nothing.
+func (r *Recorder) Logger(level logos.Level) logos.Logger {
+	return logos.NewLogger(level, r, io.Discard)
+}
+
+// Format records the entry. It implements logos.Formatter.
+func (r *Recorder) Format(level logos.Level, entry logos.Entry) string {
+	if entry.Fields != nil {
+		fields := make(logos.Fields, len(entry.Fields))
+		for key, value := range entry.Fields {
+			fields[key] = value
+		}
+		entry.Fields = fields
+	}
+	recorded := Entry{Entry: entry, Level: level}
+
+	r.mu.Lock()
+	r.entries = append(r.entries, recorded)
+	onEntry := r.onEntry
+	r.mu.Unlock()
+
+	if onEntry != nil {
+		onEntry(recorded)
+	}
+	if r.next == nil {
+		return ""
+	}
+	return r.next.Format(level, entry)
+}
+
+// Entries returns a copy of the recorded entries, in the order they were logged.
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+// Len returns the number of recorded entries.
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.entries)
+}
+
+// Reset discards the recorded entries.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+// Find returns the entries logged at level whose message contains msg and which carry
+// every field in fields with an equal value. Numbers compare equal across types, so
+// logos.Fields{"count": 3} matches an int64 field of 3.
+func (r *Recorder) Find(level logos.Level, msg string, fields ...logos.Fields) []Entry {
+	var found []Entry
+	for _, entry := range r.Entries() {
+		if entry.Level == level && strings.Contains(entry.Msg, msg) && hasFields(entry, fields) {
+			found = append(found, entry)
+		}
+	}
+	return found
+}
+
+// Logged reports whether an entry matching level, msg and fields was recorded (see Find).
+func (r *Recorder) Logged(level logos.Level, msg string, fields ...logos.Fields) bool {
+	return len(r.Find(level, msg, fields...)) > 0
+}
+
+// hasFields reports whether entry carries every expected field.
+func hasFields(entry Entry, expected []logos.Fields) bool {
+	for _, fields := range expected {
+		for key, want := range fields {
+			got, ok := entry.Field(key)
+			if !ok || !equalValues(want, got) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// equalValues compares field values, treating numbers of different types as equal when
+// they represent the same value.
+func equalValues(want, got any) bool {
+	if reflect.DeepEqual(want, got) {
+		return true
+	}
+	w, wok := number(want)
+	g, gok := number(got)
+	return wok && gok && w == g
+}
+
+// number converts integer and floating-point values to float64.
+func number(value any) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
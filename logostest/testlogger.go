@@ -0,0 +1,157 @@
+package logostest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/goodblaster/logos"
+)
+
+// TestLogger is a logos.Logger for tests. Entries are written as text through t.Log, so they
+// are shown with the test that logged them, and recorded for the assertion methods.
+// Pass the embedded Logger to the code under test; loggers derived from it are recorded too.
+type TestLogger struct {
+	logos.Logger
+	Recorder *Recorder
+	t        testing.TB
+	w        *testWriter
+}
+
+// NewTestLogger returns a TestLogger for t at LevelTrace, the most verbose built-in level, so
+// that every entry is recorded. Output written after the test has finished, for example by a
+// goroutine it left running, is dropped instead of panicking.
+func NewTestLogger(t testing.TB) *TestLogger {
+	t.Helper()
+
+	w := &testWriter{t: t}
+	t.Cleanup(w.close)
+
+	recorder := &Recorder{next: logos.TextFormatter()}
+	return &TestLogger{
+		Logger:   logos.NewLogger(logos.LevelTrace, recorder, w),
+		Recorder: recorder,
+		t:        t,
+		w:        w,
+	}
+}
+
+// Entries returns a copy of the recorded entries, in the order they were logged.
+func (l *TestLogger) Entries() []Entry {
+	return l.Recorder.Entries()
+}
+
+// RequireLogged stops the test with t.FailNow unless an entry at level, whose message
+// contains msg and which carries the given fields, was logged (see Recorder.Find).
+// It must be called from the goroutine running the test.
+func (l *TestLogger) RequireLogged(level logos.Level, msg string, fields ...logos.Fields) {
+	l.t.Helper()
+	if !l.AssertLogged(level, msg, fields...) {
+		l.t.FailNow()
+	}
+}
+
+// AssertLogged is like RequireLogged but marks the test as failed and continues.
+// It reports whether a matching entry was found.
+func (l *TestLogger) AssertLogged(level logos.Level, msg string, fields ...logos.Fields) bool {
+	l.t.Helper()
+	if l.Recorder.Logged(level, msg, fields...) {
+		return true
+	}
+
+	var want strings.Builder
+	fmt.Fprintf(&want, "%s entry containing %q", level, msg)
+	for _, f := range fields {
+		for _, key := range sortedKeys(f) {
+			fmt.Fprintf(&want, " %s=%v", key, f[key])
+		}
+	}
+	l.t.Errorf("no %s was logged%s", want.String(), l.describeEntries())
+	return false
+}
+
+// NoErrorsLogged marks the test as failed if an entry at LevelError or above was logged.
+// Print entries are not errors.
+func (l *TestLogger) NoErrorsLogged() {
+	l.t.Helper()
+	var errs []string
+	for _, entry := range l.Recorder.Entries() {
+		if atOrAbove(entry.Level, logos.LevelError) {
+			errs = append(errs, "\n\t"+entry.String())
+		}
+	}
+	if len(errs) > 0 {
+		l.t.Errorf("%d error entries were logged:%s", len(errs), strings.Join(errs, ""))
+	}
+}
+
+// FailOnLevel makes every later entry at level or above mark the test as failed as soon
+// as it is logged, from whichever goroutine logs it. Print entries are ignored.
+func (l *TestLogger) FailOnLevel(level logos.Level) {
+	t, w := l.t, l.w
+	l.Recorder.mu.Lock()
+	defer l.Recorder.mu.Unlock()
+	l.Recorder.onEntry = func(entry Entry) {
+		if atOrAbove(entry.Level, level) && !w.finished() {
+			t.Errorf("unexpected entry logged: %s", entry.String())
+		}
+	}
+}
+
+// describeEntries lists the recorded entries for a failure message.
+func (l *TestLogger) describeEntries() string {
+	entries := l.Recorder.Entries()
+	if len(entries) == 0 {
+		return "; nothing was logged"
+	}
+	var b strings.Builder
+	b.WriteString("; logged entries:")
+	for _, entry := range entries {
+		b.WriteString("\n\t" + entry.String())
+	}
+	return b.String()
+}
+
+// atOrAbove reports whether level is at or above threshold, ignoring LevelPrint.
+func atOrAbove(level, threshold logos.Level) bool {
+	return level != logos.LevelPrint && level.AtLeast(threshold)
+}
+
+// testWriter writes each line through t.Log until the test finishes.
+type testWriter struct {
+	t    testing.TB
+	mu   sync.Mutex
+	done bool
+}
+
+func (w *testWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if !w.done {
+		w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+	return len(p), nil
+}
+
+func (w *testWriter) finished() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.done
+}
+
+func (w *testWriter) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.done = true
+}
+
+func sortedKeys(fields logos.Fields) []string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
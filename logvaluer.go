@@ -0,0 +1,146 @@
+package logos
+
+import (
+	"fmt"
+)
+
+// MaxLogValueDepth bounds how many LogValue or MarshalLog calls are chained to resolve a
+// single value, and how deeply maps and slices are searched for values to resolve.
+const MaxLogValueDepth = 16
+
+// LogValuer is implemented by types that choose their own log representation, for example
+// to log a compact, safe subset of a struct instead of every exported field.
+// LogValue is only called for entries that pass the level check. It may return another
+// LogValuer, which is resolved in turn.
+type LogValuer interface {
+	LogValue() any
+}
+
+// LogMarshaler is like LogValuer for representations that can fail. If MarshalLog returns
+// an error, the field is replaced by a FieldError marker and the rest of the entry is written.
+type LogMarshaler interface {
+	MarshalLog() (any, error)
+}
+
+// resolveFields resolves the LogValuer and LogMarshaler values in fields.
+// The map is copied only if something was resolved.
+func resolveFields(fields Fields) Fields {
+	var resolved Fields
+	for key, value := range fields {
+		newValue, changed := resolveLogValue(value, 0)
+		if !changed {
+			continue
+		}
+		if resolved == nil {
+			resolved = make(Fields, len(fields))
+			for k, v := range fields {
+				resolved[k] = v
+			}
+		}
+		resolved[key] = newValue
+	}
+	if resolved == nil {
+		return fields
+	}
+	return resolved
+}
+
+// resolveLogValue replaces LogValuer and LogMarshaler values with their representation,
+// also inside map[string]any (including Fields) and []any, reporting whether anything changed.
+// Panics in LogValue and MarshalLog are recovered and reported as a FieldError.
+func resolveLogValue(value any, depth int) (resolved any, changed bool) {
+	for chained := 0; ; chained++ {
+		var next any
+		var err error
+		switch v := value.(type) {
+		case LogValuer:
+			next, err = callLogValuer(v)
+		case LogMarshaler:
+			next, err = callLogMarshaler(v)
+		default:
+			return resolveContainer(value, depth, changed)
+		}
+		if err != nil {
+			return newFieldError(value, err), true
+		}
+		if chained >= MaxLogValueDepth {
+			return newFieldError(value, fmt.Errorf("LogValue chain exceeds limit of %d", MaxLogValueDepth)), true
+		}
+		value, changed = next, true
+	}
+}
+
+// resolveContainer resolves the elements of the generic containers.
+func resolveContainer(value any, depth int, changed bool) (any, bool) {
+	if depth >= MaxLogValueDepth {
+		return value, changed
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		return resolveMap(v, depth, changed)
+	case Group:
+		m, c := resolveMap(v, depth, changed)
+		if c {
+			if resolved, ok := m.(map[string]any); ok {
+				return Group(resolved), true
+			}
+		}
+		return m, c
+	case []any:
+		var out []any
+		for i, item := range v {
+			newItem, c := resolveLogValue(item, depth+1)
+			if !c {
+				continue
+			}
+			if out == nil {
+				out = append([]any(nil), v...)
+			}
+			out[i] = newItem
+		}
+		if out != nil {
+			return out, true
+		}
+	}
+	return value, changed
+}
+
+func resolveMap(m map[string]any, depth int, changed bool) (any, bool) {
+	var out map[string]any
+	for key, item := range m {
+		newItem, c := resolveLogValue(item, depth+1)
+		if !c {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]any, len(m))
+			for k, v := range m {
+				out[k] = v
+			}
+		}
+		out[key] = newItem
+	}
+	if out != nil {
+		return out, true
+	}
+	return m, changed
+}
+
+func callLogValuer(v LogValuer) (value any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("LogValue panicked: %v", r)
+		}
+	}()
+	return v.LogValue(), nil
+}
+
+func callLogMarshaler(v LogMarshaler) (value any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("MarshalLog panicked: %v", r)
+		}
+	}()
+	return v.MarshalLog()
+}
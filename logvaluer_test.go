@@ -0,0 +1,124 @@
+package logos
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testUser struct {
+	ID       int
+	Email    string
+	Password string
+}
+
+func (u testUser) LogValue() any {
+	return map[string]any{"id": u.ID}
+}
+
+type countingValuer struct{ calls *int }
+
+func (v countingValuer) LogValue() any {
+	*v.calls++
+	return "expensive"
+}
+
+type chainValuer struct{ n int }
+
+func (v chainValuer) LogValue() any {
+	if v.n == 0 {
+		return "done"
+	}
+	return chainValuer{n: v.n - 1}
+}
+
+type failingMarshaler struct{}
+
+func (failingMarshaler) MarshalLog() (any, error) {
+	return nil, fmt.Errorf("not available")
+}
+
+type panickingValuer struct{}
+
+func (panickingValuer) LogValue() any {
+	panic("boom")
+}
+
+func TestLogValuer_Formatters(t *testing.T) {
+	user := testUser{ID: 7, Email: "a@b.c", Password: "hunter2"}
+
+	for format := range FormatNames {
+		buf := &bytes.Buffer{}
+		NewLogger(LevelInfo, NewFormatter(format), buf).With("user", user).Info("hello")
+		out := buf.String()
+		assert.NotContains(t, out, "hunter2", FormatNames[format])
+		assert.NotContains(t, out, "a@b.c", FormatNames[format])
+		assert.Contains(t, out, "id", FormatNames[format])
+	}
+
+	// Formatters also resolve values when called directly.
+	line := JSONFormatter().Format(LevelInfo, Entry{Fields: Fields{"user": user}})
+	assert.Contains(t, line, `"user":{"id":7}`)
+}
+
+func TestLogValuer_OnlyForEnabledLevels(t *testing.T) {
+	buf := &bytes.Buffer{}
+	calls := 0
+	log := NewLogger(LevelInfo, JSONFormatter(), buf).With("value", countingValuer{calls: &calls})
+
+	log.Debug("filtered")
+	assert.Equal(t, 0, calls)
+
+	log.Info("logged")
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "expensive", Map(buf).Field("value"))
+}
+
+func TestLogValuer_Nested(t *testing.T) {
+	buf := &bytes.Buffer{}
+	NewLogger(LevelInfo, JSONFormatter(), buf).WithFields(Fields{
+		"users": []any{testUser{ID: 1}, testUser{ID: 2}},
+		"req":   map[string]any{"owner": testUser{ID: 3}},
+		"chain": chainValuer{n: 3},
+	}).Info("hello")
+
+	m := Map(buf)
+	assert.Equal(t, []any{map[string]any{"id": float64(1)}, map[string]any{"id": float64(2)}}, m.Field("users"))
+	assert.Equal(t, map[string]any{"owner": map[string]any{"id": float64(3)}}, m.Field("req"))
+	assert.Equal(t, "done", m.Field("chain"))
+}
+
+func TestLogValuer_Errors(t *testing.T) {
+	buf := &bytes.Buffer{}
+	log := NewLogger(LevelInfo, TextFormatter(), buf)
+
+	log.WithFields(Fields{
+		"failing":   failingMarshaler{},
+		"panicking": panickingValuer{},
+		"endless":   chainValuer{n: MaxLogValueDepth + 5},
+		"ok":        1,
+	}).Info("hello")
+
+	out := buf.String()
+	assert.Contains(t, out, "failing=<marshal_error type=logos.failingMarshaler: not available>")
+	assert.Contains(t, out, "panicking=<marshal_error type=logos.panickingValuer: LogValue panicked: boom>")
+	assert.Contains(t, out, "endless=<marshal_error type=logos.chainValuer: LogValue chain exceeds limit")
+	assert.Contains(t, out, "ok=1")
+	assert.Contains(t, out, "hello")
+}
+
+func TestLogValuer_BeforeTransformers(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var seen any
+	NewLogger(LevelInfo, JSONFormatter(), buf).
+		WithTransformer(TransformerFunc(func(level Level, entry Entry) Entry {
+			seen = entry.Fields["user"]
+			return entry
+		})).
+		With("user", testUser{ID: 9}).
+		Info("hello")
+
+	assert.Equal(t, map[string]any{"id": 9}, seen)
+}
@@ -0,0 +1,88 @@
+package logos
+
+import (
+	"net"
+	"sync"
+	"time"
+
+	"github.com/goodblaster/errors"
+)
+
+// DefaultNetworkTimeout bounds dialing and each write when NetworkOptions.Timeout is zero.
+const DefaultNetworkTimeout = 5 * time.Second
+
+// NetworkOptions configures a NetworkWriter.
+type NetworkOptions struct {
+	Network string        // "tcp", "udp" or "unix", as accepted by net.Dial.
+	Address string        // Address to connect to, such as "logs.internal:5170".
+	Timeout time.Duration // Bounds dialing and each write. Defaults to DefaultNetworkTimeout.
+}
+
+// NetworkWriter is an io.Writer sending each write, which is one entry when used by a Logger,
+// over a network connection. The connection is established on first use and re-established
+// once if a write fails, so a restarted collector does not silence the logger.
+// A NetworkWriter is safe for concurrent use.
+type NetworkWriter struct {
+	opts NetworkOptions
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewNetworkWriter validates opts and returns a writer that connects on first use.
+func NewNetworkWriter(opts NetworkOptions) (*NetworkWriter, error) {
+	switch opts.Network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram":
+	default:
+		return nil, errors.New("unsupported network %q", opts.Network)
+	}
+	if opts.Address == "" {
+		return nil, errors.New("network address is required")
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultNetworkTimeout
+	}
+	return &NetworkWriter{opts: opts}, nil
+}
+
+// Write sends p, reconnecting and retrying once if the connection has failed. A write that
+// failed after sending part of p is not retried, since the collector would receive the start
+// of the entry twice.
+func (w *NetworkWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if w.conn == nil {
+			w.conn, err = net.DialTimeout(w.opts.Network, w.opts.Address, w.opts.Timeout)
+			if err != nil {
+				w.conn = nil
+				return 0, errors.Wrap(err, "failed to connect to %s", w.opts.Address)
+			}
+		}
+
+		_ = w.conn.SetWriteDeadline(time.Now().Add(w.opts.Timeout))
+		var n int
+		if n, err = w.conn.Write(p); err == nil {
+			return n, nil
+		}
+		_ = w.conn.Close()
+		w.conn = nil
+		if n > 0 {
+			return n, errors.Wrap(err, "failed to write to %s", w.opts.Address)
+		}
+	}
+	return 0, errors.Wrap(err, "failed to write to %s", w.opts.Address)
+}
+
+// Close closes the connection, if open. The writer reconnects if used again.
+func (w *NetworkWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
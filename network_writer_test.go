@@ -0,0 +1,121 @@
+package logos
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNetworkWriter(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+
+	lines := make(chan string, 4)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				scanner := bufio.NewScanner(conn)
+				for scanner.Scan() {
+					lines <- scanner.Text()
+				}
+			}()
+		}
+	}()
+
+	w, err := NewNetworkWriter(NetworkOptions{Network: "tcp", Address: listener.Addr().String()})
+	assert.NoError(t, err)
+	log := NewLogger(LevelInfo, mustPatternFormatter(t, "%{level} %{msg}"), w)
+
+	log.Info("first")
+	assert.Equal(t, "info first", receive(t, lines))
+
+	// A closed connection is re-established on the next write
+	assert.NoError(t, w.Close())
+	log.Warn("second")
+	assert.Equal(t, "warn second", receive(t, lines))
+	assert.NoError(t, w.Close())
+
+	_, err = NewNetworkWriter(NetworkOptions{Network: "smtp", Address: "x"})
+	assert.Error(t, err)
+	_, err = NewNetworkWriter(NetworkOptions{Network: "tcp"})
+	assert.Error(t, err)
+}
+
+// partialConn accepts the first few bytes of each write and then fails.
+type partialConn struct {
+	net.Conn
+	written []byte
+	closed  bool
+}
+
+func (c *partialConn) Write(p []byte) (int, error) {
+	n := 3
+	if len(p) < n {
+		n = len(p)
+	}
+	c.written = append(c.written, p[:n]...)
+	return n, errors.New("connection reset")
+}
+
+func (c *partialConn) SetWriteDeadline(time.Time) error { return nil }
+
+func (c *partialConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func TestNetworkWriter_PartialWrite(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer listener.Close()
+	accepted := make(chan struct{}, 1)
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			accepted <- struct{}{}
+			conn.Close()
+		}
+	}()
+
+	w, err := NewNetworkWriter(NetworkOptions{Network: "tcp", Address: listener.Addr().String()})
+	assert.NoError(t, err)
+	conn := &partialConn{}
+	w.conn = conn
+
+	// Part of the entry was sent, so it is not resent on a new connection
+	n, err := w.Write([]byte("info hello\n"))
+	assert.Equal(t, 3, n)
+	assert.ErrorContains(t, err, "connection reset")
+	assert.Equal(t, "inf", string(conn.written))
+	assert.True(t, conn.closed)
+	select {
+	case <-accepted:
+		t.Fatal("partial write was retried")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// The next write reconnects
+	_, err = w.Write([]byte("info again\n"))
+	assert.NoError(t, err)
+	assert.NoError(t, w.Close())
+}
+
+func receive(t *testing.T, lines chan string) string {
+	t.Helper()
+	select {
+	case line := <-lines:
+		return line
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for line")
+		return ""
+	}
+}
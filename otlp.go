@@ -0,0 +1,392 @@
+package logos
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goodblaster/errors"
+)
+
+// ErrExporterClosed is returned when writing to an OTLPExporter after Close.
+var ErrExporterClosed = errors.New("otlp exporter is closed")
+
+// OTLPConfig configures an OTLPExporter. Zero values fall back to the defaults noted on each field.
+type OTLPConfig struct {
+	Endpoint       string            // Full URL of the logs endpoint, e.g. http://localhost:4318/v1/logs.
+	Headers        map[string]string // Extra request headers, e.g. authentication.
+	Resource       map[string]any    // Resource attributes, e.g. {"service.name": "api"}.
+	ScopeName      string            // Instrumentation scope name. Default: "github.com/goodblaster/logos".
+	BatchSize      int               // Records per request. Default: 512.
+	MaxQueueSize   int               // Records held while the endpoint is unreachable; the oldest are dropped beyond it. Default: 8192.
+	FlushInterval  time.Duration     // Maximum time a record waits before being sent. Default: 5s.
+	MaxRetries     int               // Retries after the first failed attempt. Default: 5. Negative disables retries.
+	InitialBackoff time.Duration     // Delay before the first retry, doubled on each attempt. Default: 500ms.
+	MaxBackoff     time.Duration     // Upper bound for the retry delay. Default: 30s.
+	Timeout        time.Duration     // Per-request timeout. Default: 10s.
+	Client         *http.Client      // HTTP client. Default: a client with Timeout.
+	ErrorHandler   func(error)       // Called when a batch cannot be delivered. Optional.
+}
+
+// OTLPExporter is an io.Writer that batches OTLP JSON log records, as produced by
+// OTelFormatter, and ships them to an OTLP/HTTP endpoint using the JSON encoding.
+// Lines that are not JSON log records are sent as records with a string body.
+//
+// Batches are sent from a background goroutine when they fill up or when FlushInterval
+// elapses. Call Close to flush pending records and stop the goroutine; Close makes a single
+// attempt per batch and stops at the first failure. Records that cannot be delivered are
+// dropped and counted by Dropped.
+type OTLPExporter struct {
+	cfg      OTLPConfig
+	resource []otelKeyValue
+	dropped  atomic.Uint64
+
+	mu         sync.Mutex
+	pending    []json.RawMessage
+	partial    []byte // Incomplete trailing line from the last Write
+	closed     bool
+	overflowed int // Records dropped from a full queue since the last report
+
+	sendMu    sync.Mutex // Serializes batches so records arrive in order
+	flushCh   chan struct{}
+	done      chan struct{}
+	wg        sync.WaitGroup
+	closeOnce sync.Once
+}
+
+// NewOTLPExporter creates an OTLPExporter and starts its background flush loop.
+func NewOTLPExporter(cfg OTLPConfig) *OTLPExporter {
+	if cfg.ScopeName == "" {
+		cfg.ScopeName = "github.com/goodblaster/logos"
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 512
+	}
+	if cfg.MaxQueueSize <= 0 {
+		cfg.MaxQueueSize = 8192
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = 5 * time.Second
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 5
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = 500 * time.Millisecond
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 30 * time.Second
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: cfg.Timeout}
+	}
+
+	keys := make([]string, 0, len(cfg.Resource))
+	for key := range cfg.Resource {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	e := &OTLPExporter{
+		cfg:     cfg,
+		flushCh: make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	for _, key := range keys {
+		e.resource = append(e.resource, otelKeyValue{Key: key, Value: otelValue(cfg.Resource[key], 0)})
+	}
+
+	e.wg.Add(1)
+	go e.loop()
+	return e
+}
+
+// Write queues each complete line in p as a log record. It never blocks on the network.
+func (e *OTLPExporter) Write(p []byte) (int, error) {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return 0, ErrExporterClosed
+	}
+
+	data := append(e.partial, p...)
+	e.partial = nil
+	for {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			break
+		}
+		if line := bytes.TrimSpace(data[:i]); len(line) > 0 {
+			e.pending = append(e.pending, otlpRecord(line))
+		}
+		data = data[i+1:]
+	}
+	if len(data) > 0 {
+		e.partial = append([]byte(nil), data...)
+	}
+	if excess := len(e.pending) - e.cfg.MaxQueueSize; excess > 0 {
+		e.pending = e.pending[excess:]
+		e.overflowed += excess
+		e.dropped.Add(uint64(excess))
+	}
+	full := len(e.pending) >= e.cfg.BatchSize
+	e.mu.Unlock()
+
+	if full {
+		select {
+		case e.flushCh <- struct{}{}:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Dropped returns the number of records discarded without being delivered, because the
+// queue was full or because their batch could not be exported.
+func (e *OTLPExporter) Dropped() uint64 {
+	return e.dropped.Load()
+}
+
+// Flush sends all queued records, retrying with backoff, and returns the last delivery error.
+// After Close has begun, it stops at the first failure and drops the remaining records.
+func (e *OTLPExporter) Flush() error {
+	e.sendMu.Lock()
+	defer e.sendMu.Unlock()
+
+	var lastErr error
+	for {
+		e.mu.Lock()
+		n := len(e.pending)
+		if n > e.cfg.BatchSize {
+			n = e.cfg.BatchSize
+		}
+		batch := e.pending[:n:n]
+		e.pending = e.pending[n:]
+		e.mu.Unlock()
+
+		if len(batch) == 0 {
+			return lastErr
+		}
+		if err := e.export(batch); err != nil {
+			e.dropped.Add(uint64(len(batch)))
+			if e.stopping() {
+				e.mu.Lock()
+				e.dropped.Add(uint64(len(e.pending)))
+				e.pending = nil
+				e.mu.Unlock()
+				return err
+			}
+			lastErr = err
+		}
+	}
+}
+
+// Close flushes any queued records, including an unterminated final line, and stops the exporter.
+func (e *OTLPExporter) Close() error {
+	var err error
+	e.closeOnce.Do(func() {
+		e.mu.Lock()
+		e.closed = true
+		if line := bytes.TrimSpace(e.partial); len(line) > 0 {
+			e.pending = append(e.pending, otlpRecord(line))
+		}
+		e.partial = nil
+		e.mu.Unlock()
+
+		dropped := e.dropped.Load()
+		close(e.done)
+		e.wg.Wait()
+		err = e.Flush()
+		if n := e.dropped.Load() - dropped; err == nil && n > 0 {
+			err = errors.New("failed to export %d log records before closing", n)
+		}
+	})
+	return err
+}
+
+// loop flushes on a timer and whenever Write fills a batch.
+func (e *OTLPExporter) loop() {
+	defer e.wg.Done()
+
+	ticker := time.NewTicker(e.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-e.done:
+			return
+		case <-ticker.C:
+		case <-e.flushCh:
+		}
+		e.report(e.Flush())
+
+		e.mu.Lock()
+		overflowed := e.overflowed
+		e.overflowed = 0
+		e.mu.Unlock()
+		if overflowed > 0 {
+			e.report(errors.New("otlp queue is full: dropped %d log records", overflowed))
+		}
+	}
+}
+
+// stopping reports whether Close has begun.
+func (e *OTLPExporter) stopping() bool {
+	select {
+	case <-e.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// export sends one batch, retrying retryable failures with exponential backoff.
+func (e *OTLPExporter) export(batch []json.RawMessage) error {
+	body, err := json.Marshal(e.request(batch))
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal otlp request")
+	}
+
+	backoff := e.cfg.InitialBackoff
+	for attempt := 0; ; attempt++ {
+		retryAfter, err := e.send(body)
+		if err == nil {
+			return nil
+		}
+
+		var retryable *otlpRetryableError
+		if !errors.As(err, &retryable) || e.cfg.MaxRetries < 0 || attempt >= e.cfg.MaxRetries || e.stopping() {
+			return errors.Wrap(err, "failed to export %d log records after %d attempts", len(batch), attempt+1)
+		}
+
+		delay := backoff
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		if delay > e.cfg.MaxBackoff {
+			delay = e.cfg.MaxBackoff
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-e.done:
+			timer.Stop()
+			return errors.Wrap(err, "failed to export %d log records after %d attempts: exporter closed", len(batch), attempt+1)
+		}
+
+		backoff *= 2
+		if backoff > e.cfg.MaxBackoff {
+			backoff = e.cfg.MaxBackoff
+		}
+	}
+}
+
+// otlpRetryableError marks failures that may succeed if the request is repeated.
+type otlpRetryableError struct {
+	err error
+}
+
+func (e *otlpRetryableError) Error() string {
+	return e.err.Error()
+}
+
+// send performs a single POST. It returns the server's Retry-After delay, if any.
+func (e *OTLPExporter) send(body []byte) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.cfg.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.cfg.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.cfg.Client.Do(req)
+	if err != nil {
+		return 0, &otlpRetryableError{err: err}
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return 0, nil
+	}
+
+	err = fmt.Errorf("otlp endpoint returned %s", resp.Status)
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		var retryAfter time.Duration
+		if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil && seconds > 0 {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return retryAfter, &otlpRetryableError{err: err}
+	}
+	return 0, err
+}
+
+// request wraps a batch of records in an ExportLogsServiceRequest.
+func (e *OTLPExporter) request(batch []json.RawMessage) any {
+	type scope struct {
+		Name string `json:"name"`
+	}
+	type scopeLogs struct {
+		Scope      scope             `json:"scope"`
+		LogRecords []json.RawMessage `json:"logRecords"`
+	}
+	type resource struct {
+		Attributes []otelKeyValue `json:"attributes"`
+	}
+	type resourceLogs struct {
+		Resource  resource    `json:"resource"`
+		ScopeLogs []scopeLogs `json:"scopeLogs"`
+	}
+
+	return struct {
+		ResourceLogs []resourceLogs `json:"resourceLogs"`
+	}{
+		ResourceLogs: []resourceLogs{{
+			Resource: resource{Attributes: e.resource},
+			ScopeLogs: []scopeLogs{{
+				Scope:      scope{Name: e.cfg.ScopeName},
+				LogRecords: batch,
+			}},
+		}},
+	}
+}
+
+// report passes a delivery error to the configured error handler.
+func (e *OTLPExporter) report(err error) {
+	if err != nil && e.cfg.ErrorHandler != nil {
+		e.cfg.ErrorHandler(err)
+	}
+}
+
+// otlpRecord returns line as a log record, wrapping lines that are not JSON objects
+// (for example, output from a text formatter) as the body of a new record.
+func otlpRecord(line []byte) json.RawMessage {
+	if line[0] == '{' && json.Valid(line) {
+		return append(json.RawMessage(nil), line...)
+	}
+
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+	b, _ := json.Marshal(otelLogRecord{
+		TimeUnixNano:         now,
+		ObservedTimeUnixNano: now,
+		Body:                 otelString(string(line)),
+	})
+	return b
+}
@@ -0,0 +1,224 @@
+package logos
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// collector is an httptest stand-in for an OTLP/HTTP collector.
+type collector struct {
+	mu       sync.Mutex
+	requests []map[string]any
+	headers  []http.Header
+	failures int // Number of requests to reject with 503 before accepting
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.failures > 0 {
+		c.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	var m map[string]any
+	_ = json.Unmarshal(body, &m)
+	c.requests = append(c.requests, m)
+	c.headers = append(c.headers, r.Header.Clone())
+}
+
+// records returns the log records received in all requests.
+func (c *collector) records() []map[string]any {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var records []map[string]any
+	for _, req := range c.requests {
+		for _, rl := range req["resourceLogs"].([]any) {
+			for _, sl := range rl.(map[string]any)["scopeLogs"].([]any) {
+				for _, rec := range sl.(map[string]any)["logRecords"].([]any) {
+					records = append(records, rec.(map[string]any))
+				}
+			}
+		}
+	}
+	return records
+}
+
+func TestOTLPExporter_Export(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	exporter := NewOTLPExporter(OTLPConfig{
+		Endpoint: server.URL + "/v1/logs",
+		Headers:  map[string]string{"Authorization": "Bearer test"},
+		Resource: map[string]any{"service.name": "api"},
+	})
+	log := NewLogger(LevelInfo, OTelFormatter(), exporter)
+
+	log.With("user", "alice").Info("first")
+	log.Warn("second")
+	log.Debug("filtered")
+	assert.NoError(t, exporter.Close())
+
+	records := c.records()
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, map[string]any{"stringValue": "first"}, records[0]["body"])
+		assert.Equal(t, map[string]any{"stringValue": "second"}, records[1]["body"])
+		assert.EqualValues(t, OTelSeverityWarn, records[1]["severityNumber"])
+	}
+
+	assert.Equal(t, "Bearer test", c.headers[0].Get("Authorization"))
+	assert.Equal(t, "application/json", c.headers[0].Get("Content-Type"))
+
+	rl := c.requests[0]["resourceLogs"].([]any)[0].(map[string]any)
+	attrs := rl["resource"].(map[string]any)["attributes"].([]any)
+	assert.Equal(t, map[string]any{"key": "service.name", "value": map[string]any{"stringValue": "api"}}, attrs[0])
+
+	// Writing after Close fails.
+	_, err := exporter.Write([]byte("late\n"))
+	assert.ErrorIs(t, err, ErrExporterClosed)
+}
+
+func TestOTLPExporter_Batching(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	exporter := NewOTLPExporter(OTLPConfig{
+		Endpoint:      server.URL,
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+	})
+	log := NewLogger(LevelInfo, OTelFormatter(), exporter)
+
+	for i := 0; i < 5; i++ {
+		log.Infof("message %d", i)
+	}
+	assert.NoError(t, exporter.Close())
+
+	assert.Len(t, c.records(), 5)
+	for _, req := range c.requests {
+		rl := req["resourceLogs"].([]any)[0].(map[string]any)
+		sl := rl["scopeLogs"].([]any)[0].(map[string]any)
+		assert.LessOrEqual(t, len(sl["logRecords"].([]any)), 2)
+	}
+}
+
+func TestOTLPExporter_Retry(t *testing.T) {
+	c := &collector{failures: 2}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	exporter := NewOTLPExporter(OTLPConfig{
+		Endpoint:       server.URL,
+		FlushInterval:  time.Hour,
+		InitialBackoff: time.Millisecond,
+	})
+	NewLogger(LevelInfo, OTelFormatter(), exporter).Info("eventually delivered")
+	assert.NoError(t, exporter.Flush())
+	assert.Len(t, c.records(), 1)
+	assert.NoError(t, exporter.Close())
+}
+
+func TestOTLPExporter_GiveUp(t *testing.T) {
+	c := &collector{failures: 10}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	var reported error
+	exporter := NewOTLPExporter(OTLPConfig{
+		Endpoint:       server.URL,
+		FlushInterval:  10 * time.Millisecond,
+		MaxRetries:     1,
+		InitialBackoff: time.Millisecond,
+		ErrorHandler:   func(err error) { reported = err },
+	})
+	NewLogger(LevelInfo, OTelFormatter(), exporter).Info("dropped")
+
+	assert.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.failures == 8
+	}, time.Second, 5*time.Millisecond)
+	assert.NoError(t, exporter.Close())
+	assert.Error(t, reported)
+	assert.Empty(t, c.records())
+}
+
+func TestOTLPExporter_QueueLimit(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	exporter := NewOTLPExporter(OTLPConfig{
+		Endpoint:      server.URL,
+		MaxQueueSize:  3,
+		FlushInterval: time.Hour,
+	})
+	log := NewLogger(LevelInfo, OTelFormatter(), exporter)
+	for i := 0; i < 5; i++ {
+		log.Infof("message %d", i)
+	}
+	assert.Equal(t, uint64(2), exporter.Dropped())
+	assert.NoError(t, exporter.Close())
+
+	records := c.records()
+	if assert.Len(t, records, 3) {
+		assert.Equal(t, map[string]any{"stringValue": "message 2"}, records[0]["body"])
+	}
+}
+
+func TestOTLPExporter_CloseInterruptsBackoff(t *testing.T) {
+	c := &collector{failures: 100}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	exporter := NewOTLPExporter(OTLPConfig{
+		Endpoint:       server.URL,
+		BatchSize:      1,
+		FlushInterval:  time.Millisecond,
+		InitialBackoff: time.Hour,
+	})
+	log := NewLogger(LevelInfo, OTelFormatter(), exporter)
+	log.Info("first")
+	log.Info("second")
+	log.Info("third")
+	assert.Eventually(t, func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return c.failures < 100
+	}, time.Second, time.Millisecond)
+
+	start := time.Now()
+	assert.Error(t, exporter.Close())
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, uint64(3), exporter.Dropped())
+	assert.Empty(t, c.records())
+}
+
+func TestOTLPExporter_NonJSONLines(t *testing.T) {
+	c := &collector{}
+	server := httptest.NewServer(c)
+	defer server.Close()
+
+	exporter := NewOTLPExporter(OTLPConfig{Endpoint: server.URL})
+	NewLogger(LevelInfo, NewTextFormatter(Config{Timestamp: func() string { return "T" }}), exporter).Info("plain")
+	assert.NoError(t, exporter.Close())
+
+	records := c.records()
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, map[string]any{"stringValue": "T\tinfo\tplain"}, records[0]["body"])
+	}
+}
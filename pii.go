@@ -0,0 +1,214 @@
+package logos
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/goodblaster/errors"
+)
+
+// Prefixes of the tokens that replace protected field values.
+const (
+	PseudonymPrefix = "pii:"    // Followed by a base64url HMAC-SHA256 digest
+	EncryptedPrefix = "enc:v1:" // Followed by "<key id>:<base64url nonce and ciphertext>"
+)
+
+// pseudonymSize is the number of HMAC-SHA256 bytes kept in a pseudonym (128 bits).
+const pseudonymSize = 16
+
+// Errors returned by DecryptPII.
+var (
+	ErrNotEncrypted  = errors.New("value is not an encrypted pii token")
+	ErrUnknownPIIKey = errors.New("unknown pii key id")
+)
+
+// PIIMode selects how a protected field is rewritten.
+type PIIMode int
+
+const (
+	// PIIPseudonymize replaces the value with a keyed HMAC: the same input always yields
+	// the same token, so values stay correlatable without being recoverable.
+	PIIPseudonymize PIIMode = iota
+	// PIIEncrypt replaces the value with its AES-GCM encryption under the current key,
+	// so it can be recovered with DecryptPII by whoever holds the key.
+	PIIEncrypt
+)
+
+// PIIOptions configures a PIIProtector.
+type PIIOptions struct {
+	Fields  map[string]PIIMode // Top-level field keys to protect, matched like redaction keys
+	HMACKey []byte             // Key for PIIPseudonymize. Required if any field is pseudonymized.
+	KeyID   string             // ID of the key in Keys used to encrypt. Required if any field is encrypted.
+	Keys    map[string][]byte  // AES keys (16, 24 or 32 bytes) by ID. Old keys stay listed for decryption.
+}
+
+// PIIProtector is a Transformer that pseudonymizes or encrypts configured fields before
+// entries are formatted, so it works with every formatter. Strings are protected as is;
+// other values are protected in their JSON encoding.
+type PIIProtector struct {
+	fields  map[string]PIIMode
+	hmacKey []byte
+	keyID   string
+	aead    cipher.AEAD
+}
+
+// NewPIIProtector validates the options and creates a PIIProtector.
+func NewPIIProtector(opts PIIOptions) (*PIIProtector, error) {
+	p := &PIIProtector{
+		fields:  make(map[string]PIIMode, len(opts.Fields)),
+		hmacKey: opts.HMACKey,
+		keyID:   opts.KeyID,
+	}
+
+	pseudonymize, encrypt := false, false
+	for key, mode := range opts.Fields {
+		switch mode {
+		case PIIPseudonymize:
+			pseudonymize = true
+		case PIIEncrypt:
+			encrypt = true
+		default:
+			return nil, errors.New("invalid pii mode %d for field %q", mode, key)
+		}
+		p.fields[normalizeRedactKey(key)] = mode
+	}
+
+	if pseudonymize && len(opts.HMACKey) == 0 {
+		return nil, errors.New("pii: HMACKey is required to pseudonymize fields")
+	}
+	if encrypt {
+		if opts.KeyID == "" || strings.Contains(opts.KeyID, ":") {
+			return nil, errors.New("pii: KeyID must be set and must not contain ':'")
+		}
+		key, ok := opts.Keys[opts.KeyID]
+		if !ok {
+			return nil, errors.Wrap(ErrUnknownPIIKey, "pii: key %q", opts.KeyID)
+		}
+		aead, err := newPIIAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		p.aead = aead
+	}
+
+	return p, nil
+}
+
+// WithPII returns a new Logger that protects fields with p before anything is written,
+// including by tee loggers.
+func (logger Logger) WithPII(p *PIIProtector) Logger {
+	return logger.WithTransformer(p)
+}
+
+// Transform protects the configured fields of the entry. The entry's fields map is
+// copied, not modified.
+func (p *PIIProtector) Transform(level Level, entry Entry) Entry {
+	var fields Fields
+	for key, value := range entry.Fields {
+		mode, ok := p.fields[normalizeRedactKey(key)]
+		if !ok || value == nil {
+			continue
+		}
+		if fields == nil {
+			fields = make(Fields, len(entry.Fields))
+			for k, v := range entry.Fields {
+				fields[k] = v
+			}
+		}
+		fields[key] = p.protect(mode, value)
+	}
+	if fields != nil {
+		entry.Fields = fields
+	}
+	return entry
+}
+
+// Pseudonymize returns the token PIIPseudonymize would log for value.
+func (p *PIIProtector) Pseudonymize(value any) string {
+	mac := hmac.New(sha256.New, p.hmacKey)
+	mac.Write(piiPlaintext(value))
+	return PseudonymPrefix + base64.RawURLEncoding.EncodeToString(mac.Sum(nil)[:pseudonymSize])
+}
+
+// protect rewrites a single value. Encryption failures are logged in place of the value,
+// never the value itself.
+func (p *PIIProtector) protect(mode PIIMode, value any) string {
+	if mode == PIIPseudonymize {
+		return p.Pseudonymize(value)
+	}
+
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Sprintf("<pii_error: %v>", err)
+	}
+	sealed := p.aead.Seal(nonce, nonce, piiPlaintext(value), []byte(p.keyID))
+	return EncryptedPrefix + p.keyID + ":" + base64.RawURLEncoding.EncodeToString(sealed)
+}
+
+// DecryptPII recovers the plaintext of a token produced by PIIEncrypt, using the key
+// named in the token. Non-string values are returned in their JSON encoding.
+func DecryptPII(token string, keys map[string][]byte) (string, error) {
+	rest, ok := strings.CutPrefix(token, EncryptedPrefix)
+	if !ok {
+		return "", ErrNotEncrypted
+	}
+	keyID, payload, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", ErrNotEncrypted
+	}
+	key, ok := keys[keyID]
+	if !ok {
+		return "", errors.Wrap(ErrUnknownPIIKey, "pii: key %q", keyID)
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return "", errors.Wrap(err, "pii: invalid token encoding")
+	}
+
+	aead, err := newPIIAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("pii: token is too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(keyID))
+	if err != nil {
+		return "", errors.Wrap(err, "pii: failed to decrypt token")
+	}
+	return string(plaintext), nil
+}
+
+// newPIIAEAD creates an AES-GCM cipher from a 16, 24 or 32 byte key.
+func newPIIAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "pii: invalid aes key")
+	}
+	return cipher.NewGCM(block)
+}
+
+// piiPlaintext returns the bytes that are hashed or encrypted for a value.
+func piiPlaintext(value any) []byte {
+	switch v := value.(type) {
+	case string:
+		return []byte(v)
+	case []byte:
+		return v
+	case fmt.Stringer:
+		return []byte(v.String())
+	}
+	b, err := json.Marshal(value)
+	if err != nil {
+		return []byte(fmt.Sprint(value))
+	}
+	return b
+}
@@ -0,0 +1,123 @@
+package logos
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/goodblaster/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+var testPIIKeys = map[string][]byte{
+	"k1": []byte("0123456789abcdef0123456789abcdef"),
+	"k2": []byte("fedcba9876543210"),
+}
+
+func TestPIIProtector_Pseudonymize(t *testing.T) {
+	p, err := NewPIIProtector(PIIOptions{
+		Fields:  map[string]PIIMode{"email": PIIPseudonymize, "user_ip": PIIPseudonymize},
+		HMACKey: []byte("secret"),
+	})
+	assert.NoError(t, err)
+
+	buf := &bytes.Buffer{}
+	log := NewLogger(LevelInfo, JSONFormatter(), buf).WithPII(p)
+
+	log.WithFields(Fields{"email": "alice@example.com", "User-IP": net.ParseIP("10.0.0.1"), "plan": "pro"}).Info("signup")
+	first := Map(buf)
+	log.With("email", "alice@example.com").Info("login")
+	second := Map(buf)
+
+	token := first.Field("email").(string)
+	assert.True(t, strings.HasPrefix(token, PseudonymPrefix), token)
+	assert.Equal(t, token, second.Field("email"))
+	assert.Equal(t, p.Pseudonymize("alice@example.com"), token)
+	assert.Equal(t, p.Pseudonymize("10.0.0.1"), first.Field("User-IP"))
+	assert.Equal(t, "pro", first.Field("plan"))
+	assert.NotEqual(t, token, p.Pseudonymize("bob@example.com"))
+
+	other, _ := NewPIIProtector(PIIOptions{Fields: map[string]PIIMode{"email": PIIPseudonymize}, HMACKey: []byte("other")})
+	assert.NotEqual(t, token, other.Pseudonymize("alice@example.com"))
+}
+
+func TestPIIProtector_Encrypt(t *testing.T) {
+	p, err := NewPIIProtector(PIIOptions{
+		Fields: map[string]PIIMode{"email": PIIEncrypt, "address": PIIEncrypt},
+		KeyID:  "k1",
+		Keys:   testPIIKeys,
+	})
+	assert.NoError(t, err)
+
+	fields := Fields{"email": "alice@example.com", "address": map[string]any{"city": "Paris"}}
+	entry := p.Transform(LevelInfo, Entry{Fields: fields})
+
+	token := entry.Fields["email"].(string)
+	assert.True(t, strings.HasPrefix(token, EncryptedPrefix+"k1:"), token)
+	assert.Equal(t, "alice@example.com", fields["email"]) // Original fields are untouched
+
+	plain, err := DecryptPII(token, testPIIKeys)
+	assert.NoError(t, err)
+	assert.Equal(t, "alice@example.com", plain)
+
+	plain, err = DecryptPII(entry.Fields["address"].(string), testPIIKeys)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"city":"Paris"}`, plain)
+
+	// Encryption is randomized.
+	again := p.Transform(LevelInfo, Entry{Fields: fields})
+	assert.NotEqual(t, token, again.Fields["email"])
+}
+
+func TestPIIProtector_KeyRotation(t *testing.T) {
+	old, _ := NewPIIProtector(PIIOptions{Fields: map[string]PIIMode{"email": PIIEncrypt}, KeyID: "k1", Keys: testPIIKeys})
+	current, _ := NewPIIProtector(PIIOptions{Fields: map[string]PIIMode{"email": PIIEncrypt}, KeyID: "k2", Keys: testPIIKeys})
+
+	oldToken := old.Transform(LevelInfo, Entry{Fields: Fields{"email": "a@b.c"}}).Fields["email"].(string)
+	newToken := current.Transform(LevelInfo, Entry{Fields: Fields{"email": "a@b.c"}}).Fields["email"].(string)
+	assert.True(t, strings.HasPrefix(newToken, EncryptedPrefix+"k2:"))
+
+	for _, token := range []string{oldToken, newToken} {
+		plain, err := DecryptPII(token, testPIIKeys)
+		assert.NoError(t, err)
+		assert.Equal(t, "a@b.c", plain)
+	}
+
+	_, err := DecryptPII(oldToken, map[string][]byte{"k2": testPIIKeys["k2"]})
+	assert.True(t, errors.Is(err, ErrUnknownPIIKey))
+
+	// A token cannot be moved to another key ID.
+	forged := strings.Replace(newToken, ":k2:", ":k1:", 1)
+	_, err = DecryptPII(forged, testPIIKeys)
+	assert.Error(t, err)
+
+	_, err = DecryptPII("alice@example.com", testPIIKeys)
+	assert.True(t, errors.Is(err, ErrNotEncrypted))
+}
+
+func TestPIIProtector_AllFormatters(t *testing.T) {
+	p, _ := NewPIIProtector(PIIOptions{Fields: map[string]PIIMode{"email": PIIPseudonymize}, HMACKey: []byte("secret")})
+	token := p.Pseudonymize("alice@example.com")
+
+	for format := range FormatNames {
+		buf := &bytes.Buffer{}
+		NewLogger(LevelInfo, NewFormatter(format), buf).WithPII(p).With("email", "alice@example.com").Info("hello")
+		assert.NotContains(t, buf.String(), "alice@example.com", FormatNames[format])
+		assert.Contains(t, buf.String(), token, FormatNames[format])
+	}
+}
+
+func TestNewPIIProtector_Errors(t *testing.T) {
+	_, err := NewPIIProtector(PIIOptions{Fields: map[string]PIIMode{"email": PIIPseudonymize}})
+	assert.Error(t, err)
+
+	_, err = NewPIIProtector(PIIOptions{Fields: map[string]PIIMode{"email": PIIEncrypt}, KeyID: "missing", Keys: testPIIKeys})
+	assert.True(t, errors.Is(err, ErrUnknownPIIKey))
+
+	_, err = NewPIIProtector(PIIOptions{Fields: map[string]PIIMode{"email": PIIEncrypt}, KeyID: "bad", Keys: map[string][]byte{"bad": []byte("short")}})
+	assert.Error(t, err)
+
+	_, err = NewPIIProtector(PIIOptions{Fields: map[string]PIIMode{"email": PIIMode(9)}})
+	assert.Error(t, err)
+}
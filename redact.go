@@ -0,0 +1,322 @@
+package logos
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// DefaultRedactMask replaces redacted values.
+const DefaultRedactMask = "[REDACTED]"
+
+// redactMaxDepth bounds how deeply nested values are scanned for sensitive keys.
+const redactMaxDepth = 16
+
+// DefaultRedactKeys are the field keys masked by a Redactor when RedactorOptions.Keys is nil.
+var DefaultRedactKeys = []string{
+	"password", "passwd", "pwd", "secret", "client_secret",
+	"token", "access_token", "refresh_token", "id_token",
+	"authorization", "proxy_authorization", "cookie", "set_cookie",
+	"api_key", "apikey", "x_api_key", "private_key",
+}
+
+// SecretPattern describes a secret that can appear inside free text.
+// If Regexp has a capture group, only the first group is masked; otherwise the whole match is.
+type SecretPattern struct {
+	Name     string
+	Regexp   *regexp.Regexp
+	Validate func(match string) bool // Optional: rejects false positives, e.g. a Luhn check
+}
+
+// Built-in secret patterns.
+var (
+	PatternBearerToken = SecretPattern{
+		Name:   "bearer_token",
+		Regexp: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`),
+	}
+	PatternAWSAccessKey = SecretPattern{
+		Name:   "aws_access_key",
+		Regexp: regexp.MustCompile(`\b(?:AKIA|ASIA|AGPA|AIDA|AROA|ANPA|ANVA|AIPA)[0-9A-Z]{16}\b`),
+	}
+	PatternAWSSecretKey = SecretPattern{
+		Name:   "aws_secret_key",
+		Regexp: regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key\s*[=:]\s*"?([A-Za-z0-9/+=]{40})\b`),
+	}
+	PatternJWT = SecretPattern{
+		Name:   "jwt",
+		Regexp: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+	}
+	PatternCreditCard = SecretPattern{
+		Name:     "credit_card",
+		Regexp:   regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+		Validate: luhnValid,
+	}
+)
+
+// DefaultSecretPatterns are the patterns scanned by a Redactor when RedactorOptions.Patterns is nil.
+var DefaultSecretPatterns = []SecretPattern{
+	PatternBearerToken,
+	PatternAWSAccessKey,
+	PatternAWSSecretKey,
+	PatternJWT,
+	PatternCreditCard,
+}
+
+// RedactorOptions configures a Redactor.
+type RedactorOptions struct {
+	Keys         []string        // Field keys whose values are masked. Defaults to DefaultRedactKeys if nil.
+	Patterns     []SecretPattern // Secrets masked in messages, errors and string values. Defaults to DefaultSecretPatterns if nil.
+	Mask         string          // Replacement text. Defaults to DefaultRedactMask.
+	RevealPrefix int             // Characters of a masked string to keep at the start.
+	RevealSuffix int             // Characters of a masked string to keep at the end.
+}
+
+// Redactor is a Transformer that masks sensitive data before entries are formatted.
+// It masks the values of configured field keys, matched case-insensitively and ignoring
+// '-' and '_', at any depth in maps, slices and structs, and it masks secret patterns found
+// in the message, the error and string field values.
+type Redactor struct {
+	keys     map[string]bool
+	patterns []SecretPattern
+	opts     RedactorOptions
+}
+
+// NewRedactor creates a Redactor from the given options.
+func NewRedactor(opts RedactorOptions) *Redactor {
+	if opts.Keys == nil {
+		opts.Keys = DefaultRedactKeys
+	}
+	if opts.Patterns == nil {
+		opts.Patterns = DefaultSecretPatterns
+	}
+	if opts.Mask == "" {
+		opts.Mask = DefaultRedactMask
+	}
+
+	r := &Redactor{
+		keys:     make(map[string]bool, len(opts.Keys)),
+		patterns: opts.Patterns,
+		opts:     opts,
+	}
+	for _, key := range opts.Keys {
+		r.keys[normalizeRedactKey(key)] = true
+	}
+	return r
+}
+
+// WithRedactor returns a new Logger that masks sensitive data with r before anything is
+// written, including by tee loggers.
+func (logger Logger) WithRedactor(r *Redactor) Logger {
+	return logger.WithTransformer(r)
+}
+
+// Transform masks sensitive data in the entry.
+func (r *Redactor) Transform(level Level, entry Entry) Entry {
+	entry.Msg = r.RedactString(entry.Msg)
+
+	if entry.Error != nil {
+		msg := entry.Error.Error()
+		if redacted := r.RedactString(msg); redacted != msg {
+			entry.Error = &redactedError{msg: redacted, err: entry.Error}
+		}
+	}
+
+	if len(entry.Fields) > 0 {
+		var fields Fields
+		for key, value := range entry.Fields {
+			newValue, changed := r.redactField(key, value, 0)
+			if !changed {
+				continue
+			}
+			if fields == nil {
+				fields = make(Fields, len(entry.Fields))
+				for k, v := range entry.Fields {
+					fields[k] = v
+				}
+			}
+			fields[key] = newValue
+		}
+		if fields != nil {
+			entry.Fields = fields
+		}
+	}
+
+	return entry
+}
+
+// RedactString masks every secret pattern found in s.
+func (r *Redactor) RedactString(s string) string {
+	for _, pattern := range r.patterns {
+		re := pattern.Regexp
+		s = re.ReplaceAllStringFunc(s, func(match string) string {
+			if pattern.Validate != nil && !pattern.Validate(match) {
+				return match
+			}
+			if re.NumSubexp() == 0 {
+				return r.mask(match)
+			}
+			loc := re.FindStringSubmatchIndex(match)
+			if len(loc) < 4 || loc[2] < 0 {
+				return r.mask(match)
+			}
+			return match[:loc[2]] + r.mask(match[loc[2]:loc[3]]) + match[loc[3]:]
+		})
+	}
+	return s
+}
+
+// redactField redacts a value stored under key, reporting whether anything changed.
+func (r *Redactor) redactField(key string, value any, depth int) (any, bool) {
+	if r.keys[normalizeRedactKey(key)] {
+		if s, ok := value.(string); ok {
+			return r.mask(s), true
+		}
+		if value == nil {
+			return nil, false
+		}
+		return r.opts.Mask, true
+	}
+	return r.redactValue(value, depth)
+}
+
+// redactValue scans a value for sensitive keys and secret patterns, reporting whether
+// anything changed. Unchanged values are returned as is.
+func (r *Redactor) redactValue(value any, depth int) (any, bool) {
+	switch v := value.(type) {
+	case nil:
+		return nil, false
+	case string:
+		redacted := r.RedactString(v)
+		return redacted, redacted != v
+	}
+
+	if depth >= redactMaxDepth {
+		return value, false
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return value, false
+		}
+		out := make(map[string]any, rv.Len())
+		changed := false
+		iter := rv.MapRange()
+		for iter.Next() {
+			key := iter.Key().String()
+			newValue, c := r.redactField(key, iter.Value().Interface(), depth+1)
+			out[key] = newValue
+			changed = changed || c
+		}
+		if !changed {
+			return value, false
+		}
+		if _, ok := value.(Group); ok {
+			return Group(out), true
+		}
+		return out, true
+
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return value, false
+		}
+		out := make([]any, rv.Len())
+		changed := false
+		for i := 0; i < rv.Len(); i++ {
+			newValue, c := r.redactValue(rv.Index(i).Interface(), depth+1)
+			out[i] = newValue
+			changed = changed || c
+		}
+		if !changed {
+			return value, false
+		}
+		return out, true
+
+	case reflect.Ptr, reflect.Struct:
+		if rv.Kind() == reflect.Ptr && (rv.IsNil() || rv.Elem().Kind() != reflect.Struct) {
+			return value, false
+		}
+		if rv.Type().Implements(jsonMarshalerType) || rv.Type().Implements(textMarshalerType) {
+			return value, false
+		}
+		// Structs are scanned through their JSON form, so json tags decide the key names.
+		b, err := json.Marshal(value)
+		if err != nil {
+			return value, false
+		}
+		var generic any
+		if err := json.Unmarshal(b, &generic); err != nil {
+			return value, false
+		}
+		return r.redactValue(generic, depth)
+	}
+
+	return value, false
+}
+
+// mask replaces s with the mask, keeping the configured prefix and suffix if s is long
+// enough that the revealed characters make up no more than half of it.
+func (r *Redactor) mask(s string) string {
+	prefix, suffix := r.opts.RevealPrefix, r.opts.RevealSuffix
+	runes := []rune(s)
+	if prefix+suffix == 0 || (prefix+suffix)*2 > len(runes) {
+		return r.opts.Mask
+	}
+	return string(runes[:prefix]) + r.opts.Mask + string(runes[len(runes)-suffix:])
+}
+
+// normalizeRedactKey lower-cases a key and removes '-' and '_' so that
+// "API-Key", "api_key" and "apiKey" all match.
+func normalizeRedactKey(key string) string {
+	return redactKeyReplacer.Replace(strings.ToLower(key))
+}
+
+var redactKeyReplacer = strings.NewReplacer("-", "", "_", "")
+
+// luhnValid reports whether the digits in s form a 13 to 19 digit number passing the Luhn check.
+func luhnValid(s string) bool {
+	var digits []int
+	for _, c := range s {
+		if c >= '0' && c <= '9' {
+			digits = append(digits, int(c-'0'))
+		}
+	}
+	if len(digits) < 13 || len(digits) > 19 {
+		return false
+	}
+
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := digits[i]
+		if (len(digits)-1-i)%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+// redactedError replaces an error whose message contained secrets.
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the original error, so errors.Is and errors.As keep working.
+func (e *redactedError) Unwrap() error {
+	return e.err
+}
+
+// MarshalJSON encodes the redacted message as a list of lines, like wrapped errors.
+func (e *redactedError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.Split(e.msg, "\n"))
+}
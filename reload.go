@@ -0,0 +1,402 @@
+package logos
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goodblaster/errors"
+)
+
+// DefaultWatchInterval is how often a ConfigWatcher checks its file when
+// WatchOptions.Interval is zero.
+const DefaultWatchInterval = 2 * time.Second
+
+// levelUnset is the level of a config-built logger that defers to its configuration.
+// As the level of an output, it accepts every entry.
+const levelUnset = Level(math.MinInt)
+
+// liveConfig is shared by every logger derived from a config-built logger. It holds the
+// current configuration, which a ConfigWatcher replaces atomically.
+type liveConfig struct {
+	state atomic.Pointer[liveState]
+}
+
+// liveState is one built configuration.
+type liveState struct {
+	root   Logger           // The outputs: the first with the others as its tee loggers
+	level  Level            // Logger level
+	levels map[string]Level // Levels of named loggers
+	caller bool
+
+	// A ConfigWatcher closes the outputs of a replaced state once the entries being written
+	// through it are done: refs counts them, less retiredBias once the state is retired.
+	refs      atomic.Int64
+	closer    io.Closer
+	closeOnce sync.Once
+	closeErr  error
+	closed    chan struct{}
+}
+
+// retiredBias is subtracted from liveState.refs when a state is replaced, so that later
+// acquisitions fail and the last release finds exactly -retiredBias.
+const retiredBias = 1 << 62
+
+// acquire returns the current state, counted as in use until released.
+func (l *liveConfig) acquire() *liveState {
+	for {
+		if state := l.state.Load(); state.acquire() {
+			return state
+		}
+	}
+}
+
+// acquire counts a dispatch through the state. It fails once the state is retired.
+func (s *liveState) acquire() bool {
+	if s.refs.Add(1) > 0 {
+		return true
+	}
+	s.release()
+	return false
+}
+
+// release ends a dispatch, closing the outputs if it was the last one through a retired state.
+func (s *liveState) release() {
+	if s.refs.Add(-1) == -retiredBias {
+		_ = s.close()
+	}
+}
+
+// retire marks a replaced state, closing its outputs once no dispatch is using them.
+func (s *liveState) retire() {
+	if s.refs.Add(-retiredBias) == -retiredBias {
+		_ = s.close()
+	}
+}
+
+// close closes the outputs once and returns the result.
+func (s *liveState) close() error {
+	s.closeOnce.Do(func() {
+		if s.closer != nil {
+			s.closeErr = s.closer.Close()
+		}
+		close(s.closed)
+	})
+	return s.closeErr
+}
+
+// drained reports whether the outputs have been closed.
+func (s *liveState) drained() bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// logger returns a logger writing through the configuration.
+func (l *liveConfig) logger(name string) Logger {
+	level := levelUnset
+	return Logger{level: &level, sync: &sync.Mutex{}, name: name, live: l}
+}
+
+// levelFor returns the level configured for the longest prefix of the dotted name,
+// or the logger level if there is none.
+func (s *liveState) levelFor(name string) Level {
+	for name != "" {
+		if level, ok := s.levels[name]; ok {
+			return level
+		}
+		i := strings.LastIndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[:i]
+	}
+	return s.level
+}
+
+// threshold returns the level of front's outputs that have none of their own. A level set on
+// the logger itself, with WithLevel or a context override, takes precedence over the
+// configured levels.
+func (s *liveState) threshold(front Logger) Level {
+	if *front.level != levelUnset {
+		return *front.level
+	}
+	return s.levelFor(front.name)
+}
+
+// enabled reports whether one of front's outputs or tee loggers would write the level.
+func (l *liveConfig) enabled(front Logger, level Level) bool {
+	state := l.state.Load()
+	return outputEnabled(state.root, state.threshold(front), level)
+}
+
+// outputEnabled reports whether the output, or one of its tee loggers, would write the level,
+// using threshold for those without a level of their own.
+func outputEnabled(output Logger, threshold Level, level Level) bool {
+	own := *output.level
+	if own == levelUnset {
+		own = threshold
+	}
+	if level.AtLeast(own) {
+		return true
+	}
+	for _, teeLogger := range output.teeLoggers {
+		if outputEnabled(teeLogger, threshold, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// dispatch writes an entry of front through the current outputs, then to front's tee loggers.
+func (l *liveConfig) dispatch(front Logger, level Level, msg string, callFields Fields, inherited []Transformer) {
+	state := l.acquire()
+	defer state.release()
+	threshold := state.threshold(front)
+
+	transformers := inherited
+	if len(front.transformers) > 0 {
+		transformers = append(inherited[:len(inherited):len(inherited)], front.transformers...)
+	}
+
+	if outputEnabled(state.root, threshold, level) && (front.sampler == nil || level == LevelPrint || front.sampler.Sample(level, msg)) {
+		// The outputs' own fields take precedence, as a logger's fields do over call fields
+		fields := callFields
+		if len(front.fields) > 0 {
+			fields = make(Fields, len(callFields)+len(front.fields))
+			for key, value := range callFields {
+				fields[key] = value
+			}
+			for key, value := range front.fields {
+				fields[key] = value
+			}
+		}
+		state.root.overlay(front, &threshold, state.caller).dispatch(level, msg, fields, transformers)
+	}
+
+	for _, teeLogger := range front.teeLoggers {
+		teeLogger.dispatch(level, msg, callFields, transformers)
+	}
+}
+
+// overlay returns the output logger with the name, error and error handler of front,
+// which writes through it, and threshold as its level unless it has its own. Only the
+// slice of tee loggers is copied.
+func (logger Logger) overlay(front Logger, threshold *Level, caller bool) Logger {
+	if *logger.level == levelUnset {
+		logger.level = threshold
+	}
+	logger.name = front.name
+	logger.error = front.error
+	logger.caller = caller || front.caller
+	if front.errorHandler != nil {
+		logger.errorHandler = front.errorHandler
+	}
+	if len(logger.teeLoggers) > 0 {
+		tees := make([]Logger, len(logger.teeLoggers))
+		for i, teeLogger := range logger.teeLoggers {
+			tees[i] = teeLogger.overlay(front, threshold, caller)
+		}
+		logger.teeLoggers = tees
+	}
+	return logger
+}
+
+// WatchOptions configures a ConfigWatcher.
+type WatchOptions struct {
+	Interval     time.Duration // How often the file is checked. Defaults to DefaultWatchInterval.
+	ErrorHandler func(error)   // Receives errors loading a changed file. Defaults to logging them with the current configuration.
+}
+
+// ConfigWatcher polls a LoggerConfig file and applies changes to its Logger, and to every
+// logger derived from it, without a restart. Levels, per-name levels, fields, redaction,
+// sampling and outputs are replaced atomically; the logger name is kept from the first load.
+// A file that fails to load is reported and the previous configuration stays active.
+type ConfigWatcher struct {
+	path   string
+	opts   WatchOptions
+	live   *liveConfig
+	logger Logger
+
+	mu      sync.Mutex // Guards the fields below
+	modTime time.Time
+	size    int64
+	sum     [sha256.Size]byte
+	retired []*liveState // Replaced configurations whose outputs may still be in use
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// WatchConfig loads the JSON LoggerConfig at path, as FromConfig does, and checks the file
+// for changes every opts.Interval. Polling needs no file system notification support and
+// works with files replaced through symlinks, as mounted Kubernetes ConfigMaps are.
+// It fails if the initial configuration cannot be loaded.
+func WatchConfig(path string, opts WatchOptions) (*ConfigWatcher, error) {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultWatchInterval
+	}
+
+	w := &ConfigWatcher{
+		path: path,
+		opts: opts,
+		live: &liveConfig{},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to stat logger config")
+	}
+	cfg, sum, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	state, closer, err := cfg.build()
+	if err != nil {
+		return nil, err
+	}
+	state.closer = closer
+	w.live.state.Store(state)
+	w.logger = w.live.logger(cfg.Name)
+	w.modTime, w.size, w.sum = info.ModTime(), info.Size(), sum
+
+	go w.run()
+	return w, nil
+}
+
+// Logger returns the logger following the watched configuration.
+func (w *ConfigWatcher) Logger() Logger {
+	return w.logger
+}
+
+// Reload reads the file now and applies it if its content changed. Unlike a change found
+// by polling, a failure is returned rather than passed to the error handler.
+func (w *ConfigWatcher) Reload() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if info, err := os.Stat(w.path); err == nil {
+		w.modTime, w.size = info.ModTime(), info.Size()
+	}
+	return w.load()
+}
+
+// Close stops watching and closes the outputs, including those of replaced configurations
+// still finishing entries. Loggers from the watcher must not be used afterwards.
+func (w *ConfigWatcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.stop)
+		<-w.done
+	})
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, retired := range w.retired {
+		_ = retired.close()
+	}
+	w.retired = nil
+	return w.live.state.Load().close()
+}
+
+// run polls the file until Close is called.
+func (w *ConfigWatcher) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			if err := w.check(); err != nil {
+				w.report(err)
+			}
+		}
+	}
+}
+
+// check loads the file if its modification time or size changed since the last check.
+func (w *ConfigWatcher) check() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	retired := w.retired[:0]
+	for _, state := range w.retired {
+		if !state.drained() {
+			retired = append(retired, state)
+		}
+	}
+	w.retired = retired
+
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return errors.Wrap(err, "failed to stat logger config")
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return nil
+	}
+	w.modTime, w.size = info.ModTime(), info.Size()
+	return w.load()
+}
+
+// load reads and applies the file unless its content is unchanged. It must be called
+// with the lock held.
+func (w *ConfigWatcher) load() error {
+	cfg, sum, err := readConfig(w.path)
+	if err != nil {
+		return err
+	}
+	if sum == w.sum {
+		return nil
+	}
+	state, closer, err := cfg.build()
+	if err != nil {
+		return err
+	}
+	state.closer = closer
+
+	previous := w.live.state.Swap(state)
+	previous.retire()
+	if !previous.drained() {
+		w.retired = append(w.retired, previous)
+	}
+	w.sum = sum
+	return nil
+}
+
+// report passes err to the error handler, or logs it with the current configuration.
+func (w *ConfigWatcher) report(err error) {
+	err = errors.Wrap(err, "failed to reload logger config %s", w.path)
+	if w.opts.ErrorHandler != nil {
+		w.opts.ErrorHandler(err)
+		return
+	}
+	w.logger.WithError(err).Error("keeping the previous logger config")
+}
+
+// readConfig reads and decodes the LoggerConfig at path and returns it with a hash of
+// the file's content.
+func readConfig(path string) (LoggerConfig, [sha256.Size]byte, error) {
+	var cfg LoggerConfig
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, [sha256.Size]byte{}, errors.Wrap(err, "failed to read logger config")
+	}
+	sum := sha256.Sum256(b)
+	if cfg, err = decodeConfig(bytes.NewReader(b)); err != nil {
+		return cfg, sum, err
+	}
+	return cfg, sum, nil
+}
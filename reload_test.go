@@ -0,0 +1,143 @@
+package logos
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeConfig writes a config file with a modification time distinct from the previous one.
+func writeConfig(t *testing.T, path string, doc string) {
+	t.Helper()
+	assert.NoError(t, os.WriteFile(path, []byte(doc), 0o644))
+	stamp := time.Now().Add(time.Duration(len(doc)) * time.Second)
+	assert.NoError(t, os.Chtimes(path, stamp, stamp))
+}
+
+func TestWatchConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logging.json")
+	out := filepath.Join(dir, "app.log")
+	output := `"outputs": [{"format": "pattern", "pattern": "%{level} %{logger} %{msg}", "file": {"path": "` + out + `"}}]`
+	writeConfig(t, path, `{"level": "info", `+output+`}`)
+
+	var mu sync.Mutex
+	var reported []error
+	w, err := WatchConfig(path, WatchOptions{
+		Interval: 5 * time.Millisecond,
+		ErrorHandler: func(err error) {
+			mu.Lock()
+			reported = append(reported, err)
+			mu.Unlock()
+		},
+	})
+	assert.NoError(t, err)
+	defer w.Close()
+
+	db := w.Logger().Named("db") // Derived before the reload, still follows it
+	db.Debug("dropped")
+	assert.False(t, db.IsLevelEnabled(LevelDebug))
+
+	writeConfig(t, path, `{"level": "warn", "levels": {"db": "debug"}, `+output+`}`)
+	assert.Eventually(t, func() bool { return db.IsLevelEnabled(LevelDebug) }, time.Second, 5*time.Millisecond)
+	db.Debug("query")
+	w.Logger().Info("dropped")
+
+	writeConfig(t, path, `{"level": "loud", `+output+`}`)
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(reported) == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Contains(t, reported[0].Error(), `unknown level "loud"`)
+	assert.True(t, db.IsLevelEnabled(LevelDebug)) // The previous config stays active
+
+	assert.NoError(t, w.Close())
+	b, err := os.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Equal(t, "debug db query\n", string(b))
+}
+
+func TestConfigWatcher_Reload(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logging.json")
+	first, second := filepath.Join(dir, "first.log"), filepath.Join(dir, "second.log")
+	writeConfig(t, path, `{"fields": {"v": 1}, "outputs": [{"format": "json", "file": {"path": "`+first+`"}}]}`)
+
+	w, err := WatchConfig(path, WatchOptions{Interval: time.Hour})
+	assert.NoError(t, err)
+	log := w.Logger().With("user", "alice")
+	log.Info("one")
+
+	writeConfig(t, path, `{"fields": {"v": 2}, "sampling": {"first": 1}, "outputs": [{"format": "json", "file": {"path": "`+second+`"}}]}`)
+	assert.NoError(t, w.Reload())
+	log.Info("two")
+	log.Info("two") // Sampled out
+
+	writeConfig(t, path, `{"outputs": [{"format": "xml"}]}`)
+	assert.Error(t, w.Reload())
+	log.Info("three")
+	assert.NoError(t, w.Close())
+
+	lines := readLines(t, first)
+	assert.Len(t, lines, 1)
+	assert.Equal(t, map[string]any{"v": float64(1), "user": "alice"}, lines[0]["fields"])
+
+	lines = readLines(t, second)
+	assert.Len(t, lines, 2)
+	assert.Equal(t, "two", lines[0]["msg"])
+	assert.Equal(t, map[string]any{"v": float64(2), "user": "alice"}, lines[0]["fields"])
+	assert.Equal(t, "three", lines[1]["msg"])
+}
+
+func TestWatchConfig_Errors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logging.json")
+	_, err := WatchConfig(path, WatchOptions{})
+	assert.Error(t, err)
+
+	writeConfig(t, path, `{"level": "loud"}`)
+	_, err = WatchConfig(path, WatchOptions{})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `unknown level "loud"`)
+	}
+}
+
+func TestConfigWatcher_DrainsReplacedOutputs(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "logging.json")
+	first, second := filepath.Join(dir, "first.log"), filepath.Join(dir, "second.log")
+	writeConfig(t, path, `{"outputs": [{"format": "json", "file": {"path": "`+first+`"}}]}`)
+
+	var reported []error
+	w, err := WatchConfig(path, WatchOptions{
+		Interval:     time.Millisecond,
+		ErrorHandler: func(err error) { reported = append(reported, err) },
+	})
+	assert.NoError(t, err)
+	defer w.Close()
+
+	// An entry still being written through the first configuration when it is replaced
+	inFlight := w.live.acquire()
+	writeConfig(t, path, `{"outputs": [{"format": "json", "file": {"path": "`+second+`"}}]}`)
+	assert.NoError(t, w.Reload())
+	time.Sleep(10 * time.Millisecond) // Several polls
+	assert.False(t, inFlight.drained())
+
+	var failed error
+	inFlight.root.WithErrorHandler(func(err error) { failed = err }).Info("late")
+	inFlight.release()
+	assert.NoError(t, failed)
+	assert.True(t, inFlight.drained())
+
+	w.Logger().Info("current")
+	assert.NoError(t, w.Close())
+	assert.Empty(t, reported)
+	if lines := readLines(t, first); assert.Len(t, lines, 1) {
+		assert.Equal(t, "late", lines[0]["msg"])
+	}
+	assert.Len(t, readLines(t, second), 1)
+}
@@ -0,0 +1,90 @@
+package logos
+
+import (
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// DefaultSamplerTick is the sampling period used when SamplerOptions.Tick is zero.
+const DefaultSamplerTick = time.Second
+
+// samplerBuckets bounds the memory used by a sampler. Messages whose hashes share a
+// bucket share a counter, which only makes sampling slightly more aggressive for them.
+const samplerBuckets = 4096
+
+// Sampler decides whether an entry that passed the level check is written. It is called
+// with the entry's level and message before fields are resolved, so it is cheap to drop
+// entries. Implementations must be safe for concurrent use.
+type Sampler interface {
+	Sample(level Level, msg string) bool
+}
+
+// SamplerFunc adapts a function to the Sampler interface.
+type SamplerFunc func(level Level, msg string) bool
+
+// Sample calls f(level, msg).
+func (f SamplerFunc) Sample(level Level, msg string) bool {
+	return f(level, msg)
+}
+
+// SamplerOptions configures the sampler returned by NewSampler.
+type SamplerOptions struct {
+	Tick       time.Duration // Period after which counts reset. Defaults to DefaultSamplerTick.
+	First      int           // Entries with the same level and message written per tick before sampling starts.
+	Thereafter int           // After First, write every Thereafter-th entry. If zero, drop the rest of the tick.
+}
+
+// NewSampler returns a Sampler that bounds repeated entries: within each tick, the first
+// First entries with a given level and message are written, then every Thereafter-th.
+// It keeps a hot loop from flooding the output while rare messages are always written.
+func NewSampler(opts SamplerOptions) Sampler {
+	if opts.Tick <= 0 {
+		opts.Tick = DefaultSamplerTick
+	}
+	return &countSampler{opts: opts, now: time.Now}
+}
+
+// countSampler implements NewSampler with a fixed set of counters.
+type countSampler struct {
+	opts     SamplerOptions
+	now      func() time.Time
+	mu       sync.Mutex
+	counters [samplerBuckets]samplerCounter
+}
+
+type samplerCounter struct {
+	resetAt time.Time
+	count   int
+}
+
+func (s *countSampler) Sample(level Level, msg string) bool {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte{byte(level), byte(level >> 8)})
+	_, _ = h.Write([]byte(msg))
+	now := s.now()
+
+	s.mu.Lock()
+	counter := &s.counters[h.Sum32()%samplerBuckets]
+	if !now.Before(counter.resetAt) {
+		counter.resetAt = now.Add(s.opts.Tick)
+		counter.count = 0
+	}
+	counter.count++
+	n := counter.count
+	s.mu.Unlock()
+
+	if n <= s.opts.First {
+		return true
+	}
+	return s.opts.Thereafter > 0 && (n-s.opts.First)%s.opts.Thereafter == 0
+}
+
+// WithSampler returns a new Logger whose entries pass through s before being written.
+// The sampler applies to this logger's own destination only; tee loggers keep theirs.
+// LevelPrint entries are never sampled.
+func (logger Logger) WithSampler(s Sampler) Logger {
+	newLogger := logger.Copy()
+	newLogger.sampler = s
+	return newLogger
+}
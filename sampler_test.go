@@ -0,0 +1,48 @@
+package logos
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSampler(t *testing.T) {
+	s := NewSampler(SamplerOptions{First: 2, Thereafter: 3}).(*countSampler)
+	now := time.Date(2024, 1, 2, 15, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+
+	var kept []int
+	for i := 1; i <= 10; i++ {
+		if s.Sample(LevelInfo, "hot loop") {
+			kept = append(kept, i)
+		}
+	}
+	assert.Equal(t, []int{1, 2, 5, 8}, kept)
+
+	// Other messages and levels have their own counts
+	assert.True(t, s.Sample(LevelWarn, "hot loop"))
+	assert.True(t, s.Sample(LevelInfo, "rare"))
+
+	// Counts reset every tick
+	now = now.Add(DefaultSamplerTick)
+	assert.True(t, s.Sample(LevelInfo, "hot loop"))
+}
+
+func TestLogger_WithSampler(t *testing.T) {
+	buf := &bytes.Buffer{}
+	teeBuf := &bytes.Buffer{}
+	log := NewLogger(LevelDebug, TextFormatter(), buf).
+		WithSampler(NewSampler(SamplerOptions{First: 1})).
+		Tee(NewLogger(LevelDebug, TextFormatter(), teeBuf))
+
+	for i := 0; i < 3; i++ {
+		log.Info("repeated")
+		log.Print("printed")
+	}
+	assert.Equal(t, 1, strings.Count(buf.String(), "repeated"))
+	assert.Equal(t, 3, strings.Count(buf.String(), "printed"))
+	assert.Equal(t, 3, strings.Count(teeBuf.String(), "repeated")) // Tee loggers are not sampled
+}
@@ -0,0 +1,273 @@
+package logos
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ColorProfile describes how many colors a terminal can display.
+// Styles are downgraded to the profile they are rendered for.
+type ColorProfile int
+
+const (
+	// ColorProfileAuto detects the profile from the COLORTERM and TERM environment variables.
+	ColorProfileAuto ColorProfile = iota
+	// ColorProfileNone renders no colors or attributes.
+	ColorProfileNone
+	// ColorProfileANSI renders the 16 standard ANSI colors.
+	ColorProfileANSI
+	// ColorProfileANSI256 renders the xterm 256-color palette.
+	ColorProfileANSI256
+	// ColorProfileTrueColor renders 24-bit RGB colors.
+	ColorProfileTrueColor
+)
+
+// DetectColorProfile returns the color profile advertised by the environment:
+// COLORTERM=truecolor or 24bit selects true color, a TERM containing "256color" selects
+// the 256-color palette, TERM=dumb selects no colors, and anything else selects ANSI.
+func DetectColorProfile() ColorProfile {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return ColorProfileTrueColor
+	}
+
+	term := os.Getenv("TERM")
+	switch {
+	case term == "dumb":
+		return ColorProfileNone
+	case strings.Contains(term, "256color"):
+		return ColorProfileANSI256
+	}
+	return ColorProfileANSI
+}
+
+// colorKind identifies how a ColorSpec is encoded.
+type colorKind uint8
+
+const (
+	colorKindDefault colorKind = iota
+	colorKindANSI
+	colorKind256
+	colorKindRGB
+)
+
+// ColorSpec is a terminal color: one of the 16 ANSI colors, an entry of the 256-color
+// palette, or a 24-bit RGB value. The zero value is the terminal's default color.
+type ColorSpec struct {
+	kind    colorKind
+	index   uint8
+	r, g, b uint8
+}
+
+// ANSIColor returns one of the 16 standard ANSI colors (0-7 normal, 8-15 bright).
+func ANSIColor(n uint8) ColorSpec {
+	return ColorSpec{kind: colorKindANSI, index: n % 16}
+}
+
+// Color256 returns an entry of the xterm 256-color palette.
+func Color256(n uint8) ColorSpec {
+	return ColorSpec{kind: colorKind256, index: n}
+}
+
+// RGB returns a 24-bit color.
+func RGB(r, g, b uint8) ColorSpec {
+	return ColorSpec{kind: colorKindRGB, r: r, g: g, b: b}
+}
+
+// HexColor parses a color written as "#rrggbb" or "rrggbb".
+func HexColor(s string) (ColorSpec, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) != 6 {
+		return ColorSpec{}, fmt.Errorf("invalid hex color %q", s)
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return ColorSpec{}, fmt.Errorf("invalid hex color %q", s)
+	}
+	return RGB(uint8(v>>16), uint8(v>>8), uint8(v)), nil
+}
+
+// IsDefault reports whether the color is the terminal's default color.
+func (c ColorSpec) IsDefault() bool {
+	return c.kind == colorKindDefault
+}
+
+// downgrade converts the color to one the profile can display.
+func (c ColorSpec) downgrade(profile ColorProfile) ColorSpec {
+	switch {
+	case c.kind == colorKindDefault || profile == ColorProfileNone:
+		return ColorSpec{}
+	case c.kind == colorKindRGB && profile == ColorProfileANSI256:
+		return Color256(rgbTo256(c.r, c.g, c.b))
+	case c.kind == colorKindRGB && profile == ColorProfileANSI:
+		return ANSIColor(rgbToANSI(c.r, c.g, c.b))
+	case c.kind == colorKind256 && profile == ColorProfileANSI:
+		if c.index < 16 {
+			return ANSIColor(c.index)
+		}
+		r, g, b := palette256(c.index)
+		return ANSIColor(rgbToANSI(r, g, b))
+	}
+	return c
+}
+
+// sgr returns the SGR parameters selecting the color as foreground or background.
+func (c ColorSpec) sgr(background bool) string {
+	base := 30
+	if background {
+		base = 40
+	}
+	switch c.kind {
+	case colorKindANSI:
+		if c.index < 8 {
+			return strconv.Itoa(base + int(c.index))
+		}
+		return strconv.Itoa(base + 60 + int(c.index-8))
+	case colorKind256:
+		return fmt.Sprintf("%d;5;%d", base+8, c.index)
+	case colorKindRGB:
+		return fmt.Sprintf("%d;2;%d;%d;%d", base+8, c.r, c.g, c.b)
+	}
+	return ""
+}
+
+// Style combines foreground and background colors with text attributes.
+// The zero value renders text unchanged.
+type Style struct {
+	Fg        ColorSpec
+	Bg        ColorSpec
+	Bold      bool
+	Dim       bool
+	Italic    bool
+	Underline bool
+}
+
+// Sequence returns the ANSI escape sequence that starts the style for the given profile,
+// or an empty string if the style has no visible effect. ColorProfileAuto is detected
+// from the environment.
+func (s Style) Sequence(profile ColorProfile) string {
+	if profile == ColorProfileAuto {
+		profile = DetectColorProfile()
+	}
+	if profile == ColorProfileNone {
+		return ""
+	}
+
+	var params []string
+	if s.Bold {
+		params = append(params, "1")
+	}
+	if s.Dim {
+		params = append(params, "2")
+	}
+	if s.Italic {
+		params = append(params, "3")
+	}
+	if s.Underline {
+		params = append(params, "4")
+	}
+	if fg := s.Fg.downgrade(profile); !fg.IsDefault() {
+		params = append(params, fg.sgr(false))
+	}
+	if bg := s.Bg.downgrade(profile); !bg.IsDefault() {
+		params = append(params, bg.sgr(true))
+	}
+
+	if len(params) == 0 {
+		return ""
+	}
+	return "\033[" + strings.Join(params, ";") + "m"
+}
+
+// Render wraps text in the style's escape sequence and a reset, for the given profile.
+func (s Style) Render(text string, profile ColorProfile) string {
+	seq := s.Sequence(profile)
+	if seq == "" {
+		return text
+	}
+	return seq + text + string(ColorReset)
+}
+
+// ansiPalette holds the xterm default RGB values of the 16 ANSI colors.
+var ansiPalette = [16][3]uint8{
+	{0, 0, 0}, {205, 0, 0}, {0, 205, 0}, {205, 205, 0},
+	{0, 0, 238}, {205, 0, 205}, {0, 205, 205}, {229, 229, 229},
+	{127, 127, 127}, {255, 0, 0}, {0, 255, 0}, {255, 255, 0},
+	{92, 92, 255}, {255, 0, 255}, {0, 255, 255}, {255, 255, 255},
+}
+
+// cubeLevels are the channel intensities of the 6x6x6 cube in the 256-color palette.
+var cubeLevels = [6]uint8{0, 95, 135, 175, 215, 255}
+
+// palette256 returns the RGB value of a 256-color palette entry.
+func palette256(n uint8) (r, g, b uint8) {
+	switch {
+	case n < 16:
+		c := ansiPalette[n]
+		return c[0], c[1], c[2]
+	case n < 232:
+		n -= 16
+		return cubeLevels[n/36], cubeLevels[(n/6)%6], cubeLevels[n%6]
+	default:
+		gray := 8 + 10*(n-232)
+		return gray, gray, gray
+	}
+}
+
+// rgbTo256 returns the 256-color palette entry closest to an RGB value,
+// choosing between the color cube and the grayscale ramp.
+func rgbTo256(r, g, b uint8) uint8 {
+	cubeIndex := func(v uint8) uint8 {
+		best := uint8(0)
+		for i, level := range cubeLevels {
+			if absDiff(v, level) < absDiff(v, cubeLevels[best]) {
+				best = uint8(i)
+			}
+		}
+		return best
+	}
+	ri, gi, bi := cubeIndex(r), cubeIndex(g), cubeIndex(b)
+	cube := 16 + 36*ri + 6*gi + bi
+
+	avg := (int(r) + int(g) + int(b)) / 3
+	grayStep := (avg - 8 + 5) / 10
+	if grayStep < 0 {
+		grayStep = 0
+	} else if grayStep > 23 {
+		grayStep = 23
+	}
+	gray := uint8(232 + grayStep)
+
+	cr, cg, cb := palette256(cube)
+	gr, gg, gb := palette256(gray)
+	if colorDistance(r, g, b, gr, gg, gb) < colorDistance(r, g, b, cr, cg, cb) {
+		return gray
+	}
+	return cube
+}
+
+// rgbToANSI returns the ANSI color closest to an RGB value.
+func rgbToANSI(r, g, b uint8) uint8 {
+	best, bestDistance := uint8(0), -1
+	for i, c := range ansiPalette {
+		if d := colorDistance(r, g, b, c[0], c[1], c[2]); bestDistance < 0 || d < bestDistance {
+			best, bestDistance = uint8(i), d
+		}
+	}
+	return best
+}
+
+// colorDistance returns the squared Euclidean distance between two RGB values.
+func colorDistance(r1, g1, b1, r2, g2, b2 uint8) int {
+	dr, dg, db := int(r1)-int(r2), int(g1)-int(g2), int(b1)-int(b2)
+	return dr*dr + dg*dg + db*db
+}
+
+func absDiff(a, b uint8) uint8 {
+	if a > b {
+		return a - b
+	}
+	return b - a
+}
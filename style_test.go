@@ -0,0 +1,78 @@
+package logos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStyle_Sequence(t *testing.T) {
+	tests := []struct {
+		name    string
+		style   Style
+		profile ColorProfile
+		want    string
+	}{
+		{"empty", Style{}, ColorProfileTrueColor, ""},
+		{"ansi", Style{Fg: ANSIColor(1)}, ColorProfileANSI, "\033[31m"},
+		{"bright ansi", Style{Fg: ANSIColor(9)}, ColorProfileANSI, "\033[91m"},
+		{"ansi background", Style{Bg: ANSIColor(4)}, ColorProfileANSI, "\033[44m"},
+		{"256", Style{Fg: Color256(208)}, ColorProfileANSI256, "\033[38;5;208m"},
+		{"rgb", Style{Fg: RGB(1, 2, 3)}, ColorProfileTrueColor, "\033[38;2;1;2;3m"},
+		{"rgb background", Style{Bg: RGB(1, 2, 3)}, ColorProfileTrueColor, "\033[48;2;1;2;3m"},
+		{"attributes", Style{Bold: true, Dim: true, Italic: true, Underline: true}, ColorProfileANSI, "\033[1;2;3;4m"},
+		{"bold red", Style{Fg: ANSIColor(1), Bold: true}, ColorProfileANSI, "\033[1;31m"},
+		{"no colors", Style{Fg: RGB(255, 0, 0), Bold: true}, ColorProfileNone, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.style.Sequence(tt.profile))
+		})
+	}
+}
+
+func TestStyle_Downgrade(t *testing.T) {
+	// Pure red lands on the cube's red corner and the bright ANSI red.
+	red := Style{Fg: RGB(255, 0, 0)}
+	assert.Equal(t, "\033[38;5;196m", red.Sequence(ColorProfileANSI256))
+	assert.Equal(t, "\033[91m", red.Sequence(ColorProfileANSI))
+
+	// Mid gray prefers the grayscale ramp over the cube.
+	gray := Style{Fg: RGB(128, 128, 128)}
+	assert.Equal(t, "\033[38;5;244m", gray.Sequence(ColorProfileANSI256))
+
+	// 256-color entries downgrade to the nearest ANSI color.
+	assert.Equal(t, "\033[32m", Style{Fg: Color256(2)}.Sequence(ColorProfileANSI))
+	assert.Equal(t, "\033[94m", Style{Fg: Color256(63)}.Sequence(ColorProfileANSI))
+}
+
+func TestStyle_Render(t *testing.T) {
+	assert.Equal(t, "text", Style{}.Render("text", ColorProfileANSI))
+	assert.Equal(t, "\033[1mtext\033[0m", Style{Bold: true}.Render("text", ColorProfileANSI))
+}
+
+func TestHexColor(t *testing.T) {
+	c, err := HexColor("#ff8000")
+	assert.NoError(t, err)
+	assert.Equal(t, RGB(255, 128, 0), c)
+
+	_, err = HexColor("#ff80")
+	assert.Error(t, err)
+	_, err = HexColor("zzzzzz")
+	assert.Error(t, err)
+}
+
+func TestDetectColorProfile(t *testing.T) {
+	t.Setenv("COLORTERM", "truecolor")
+	t.Setenv("TERM", "xterm-256color")
+	assert.Equal(t, ColorProfileTrueColor, DetectColorProfile())
+
+	t.Setenv("COLORTERM", "")
+	assert.Equal(t, ColorProfileANSI256, DetectColorProfile())
+
+	t.Setenv("TERM", "xterm")
+	assert.Equal(t, ColorProfileANSI, DetectColorProfile())
+
+	t.Setenv("TERM", "dumb")
+	assert.Equal(t, ColorProfileNone, DetectColorProfile())
+}
@@ -0,0 +1,52 @@
+package logos
+
+import (
+	"io"
+	"os"
+	"strings"
+)
+
+// ColorEnabled reports whether colored output should be written to w. The decision follows,
+// in order of precedence:
+//   - LOG_COLOR: "always" (or true/1/on/yes) forces colors on, "never" (or false/0/off/no)
+//     forces them off, and "auto" or unset falls through to the checks below.
+//   - NO_COLOR: any non-empty value disables colors (https://no-color.org).
+//   - FORCE_COLOR: any value other than "0" or "false" enables colors.
+//   - TERM=dumb disables colors.
+//   - Otherwise colors are enabled only when w is a terminal.
+func ColorEnabled(w io.Writer) bool {
+	switch strings.ToLower(os.Getenv("LOG_COLOR")) {
+	case "always", "true", "1", "on", "yes":
+		return true
+	case "never", "false", "0", "off", "no":
+		return false
+	}
+
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+
+	if force, ok := os.LookupEnv("FORCE_COLOR"); ok {
+		switch strings.ToLower(force) {
+		case "0", "false":
+			return false
+		default:
+			return true
+		}
+	}
+
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	return IsTerminal(w)
+}
+
+// IsTerminal reports whether w is a file descriptor connected to a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(interface{ Fd() uintptr })
+	if !ok {
+		return false
+	}
+	return isTerminal(f.Fd())
+}
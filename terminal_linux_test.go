@@ -0,0 +1,20 @@
+//go:build linux
+
+package logos
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsTerminal_PTY(t *testing.T) {
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR, 0)
+	if err != nil {
+		t.Skip("no pseudo-terminal available:", err)
+	}
+	defer ptmx.Close()
+
+	assert.True(t, IsTerminal(ptmx))
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package logos
+
+// isTerminal always reports false on platforms without terminal detection.
+// Colors can still be enabled with LOG_COLOR=always or FORCE_COLOR.
+func isTerminal(fd uintptr) bool {
+	return false
+}
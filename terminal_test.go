@@ -0,0 +1,83 @@
+package logos
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// clearColorEnv unsets the variables consulted by ColorEnabled for the duration of a test.
+func clearColorEnv(t *testing.T) {
+	for _, key := range []string{"LOG_COLOR", "NO_COLOR", "FORCE_COLOR", "TERM"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	buf := &bytes.Buffer{}
+
+	t.Run("not a terminal", func(t *testing.T) {
+		clearColorEnv(t)
+		assert.False(t, ColorEnabled(buf))
+	})
+
+	t.Run("FORCE_COLOR", func(t *testing.T) {
+		clearColorEnv(t)
+		t.Setenv("FORCE_COLOR", "1")
+		assert.True(t, ColorEnabled(buf))
+		t.Setenv("FORCE_COLOR", "0")
+		assert.False(t, ColorEnabled(buf))
+	})
+
+	t.Run("NO_COLOR wins over FORCE_COLOR", func(t *testing.T) {
+		clearColorEnv(t)
+		t.Setenv("FORCE_COLOR", "1")
+		t.Setenv("NO_COLOR", "1")
+		assert.False(t, ColorEnabled(buf))
+	})
+
+	t.Run("LOG_COLOR wins over everything", func(t *testing.T) {
+		clearColorEnv(t)
+		t.Setenv("NO_COLOR", "1")
+		t.Setenv("LOG_COLOR", "always")
+		assert.True(t, ColorEnabled(buf))
+
+		t.Setenv("NO_COLOR", "")
+		t.Setenv("FORCE_COLOR", "1")
+		t.Setenv("LOG_COLOR", "never")
+		assert.False(t, ColorEnabled(buf))
+
+		t.Setenv("LOG_COLOR", "auto")
+		assert.True(t, ColorEnabled(buf))
+	})
+}
+
+func TestIsTerminal(t *testing.T) {
+	assert.False(t, IsTerminal(&bytes.Buffer{}))
+
+	r, w, err := os.Pipe()
+	if assert.NoError(t, err) {
+		defer r.Close()
+		defer w.Close()
+		assert.False(t, IsTerminal(w))
+	}
+}
+
+func TestAutoConsoleFormatter(t *testing.T) {
+	clearColorEnv(t)
+	buf := &bytes.Buffer{}
+
+	// Not a terminal: plain text layout without escape sequences.
+	fmtr := AutoConsoleFormatter(buf)
+	line := fmtr.Format(LevelInfo, Entry{Msg: "Test"})
+	assert.NotContains(t, line, "\033[")
+	assert.Contains(t, line, "\tinfo\tTest")
+
+	t.Setenv("FORCE_COLOR", "1")
+	fmtr = AutoConsoleFormatter(buf)
+	line = fmtr.Format(LevelInfo, Entry{Msg: "Test"})
+	assert.Contains(t, line, string(ColorTextGreen)+"info"+string(ColorReset))
+}
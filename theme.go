@@ -0,0 +1,149 @@
+package logos
+
+import (
+	"strings"
+	"sync"
+)
+
+// Theme assigns styles to each part of a console log line. Level styles fall back to
+// the level colors (see GetLevelColor) when a level is missing from Levels.
+type Theme struct {
+	Name      string
+	Timestamp Style
+	Levels    map[Level]Style
+	Message   Style
+	Key       Style // Field keys, including "error"
+	String    Style // String field values
+	Number    Style // Numeric field values
+	Bool      Style // Boolean field values
+	Null      Style // Null field values
+	Error     Style // Error values and fields that could not be encoded
+}
+
+// Built-in themes. "classic" reproduces the original output, where only the level is colored.
+var (
+	ThemeClassic = Theme{
+		Name: "classic",
+	}
+
+	ThemeDark = Theme{
+		Name:      "dark",
+		Timestamp: Style{Fg: Color256(245)},
+		Levels: map[Level]Style{
+			LevelTrace:     {Fg: Color256(245)},
+			LevelDebug:     {Fg: RGB(97, 175, 239)},
+			LevelInfo:      {Fg: RGB(152, 195, 121), Bold: true},
+			LevelNotice:    {Fg: RGB(86, 182, 194), Bold: true},
+			LevelWarn:      {Fg: RGB(229, 192, 123), Bold: true},
+			LevelError:     {Fg: RGB(224, 108, 117), Bold: true},
+			LevelCritical:  {Fg: RGB(255, 255, 255), Bg: RGB(224, 108, 117), Bold: true},
+			LevelAlert:     {Fg: RGB(40, 44, 52), Bg: RGB(229, 192, 123), Bold: true},
+			LevelFatal:     {Fg: RGB(255, 255, 255), Bg: RGB(190, 80, 70), Bold: true},
+			LevelEmergency: {Fg: RGB(255, 255, 255), Bg: RGB(190, 80, 70), Bold: true, Underline: true},
+			LevelPanic:     {Fg: RGB(255, 255, 255), Bg: RGB(198, 120, 221), Bold: true},
+		},
+		Key:    Style{Fg: RGB(86, 182, 194)},
+		String: Style{Fg: RGB(152, 195, 121)},
+		Number: Style{Fg: RGB(209, 154, 102)},
+		Bool:   Style{Fg: RGB(198, 120, 221)},
+		Null:   Style{Fg: Color256(245), Italic: true},
+		Error:  Style{Fg: RGB(224, 108, 117), Bold: true},
+	}
+
+	ThemeLight = Theme{
+		Name:      "light",
+		Timestamp: Style{Fg: Color256(244)},
+		Levels: map[Level]Style{
+			LevelTrace:     {Fg: Color256(244)},
+			LevelDebug:     {Fg: RGB(1, 132, 188)},
+			LevelInfo:      {Fg: RGB(80, 161, 79), Bold: true},
+			LevelNotice:    {Fg: RGB(9, 151, 179), Bold: true},
+			LevelWarn:      {Fg: RGB(193, 132, 1), Bold: true},
+			LevelError:     {Fg: RGB(228, 86, 73), Bold: true},
+			LevelCritical:  {Fg: RGB(255, 255, 255), Bg: RGB(228, 86, 73), Bold: true},
+			LevelAlert:     {Fg: RGB(255, 255, 255), Bg: RGB(193, 132, 1), Bold: true},
+			LevelFatal:     {Fg: RGB(255, 255, 255), Bg: RGB(202, 18, 67), Bold: true},
+			LevelEmergency: {Fg: RGB(255, 255, 255), Bg: RGB(202, 18, 67), Bold: true, Underline: true},
+			LevelPanic:     {Fg: RGB(255, 255, 255), Bg: RGB(166, 38, 164), Bold: true},
+		},
+		Key:    Style{Fg: RGB(1, 132, 188)},
+		String: Style{Fg: RGB(80, 161, 79)},
+		Number: Style{Fg: RGB(152, 104, 1)},
+		Bool:   Style{Fg: RGB(166, 38, 164)},
+		Null:   Style{Fg: Color256(244), Italic: true},
+		Error:  Style{Fg: RGB(228, 86, 73), Bold: true},
+	}
+
+	ThemeMono = Theme{
+		Name:      "mono",
+		Timestamp: Style{Dim: true},
+		Levels: map[Level]Style{
+			LevelTrace:     {Dim: true, Italic: true},
+			LevelDebug:     {Dim: true},
+			LevelInfo:      {Bold: true},
+			LevelNotice:    {Bold: true},
+			LevelWarn:      {Bold: true, Underline: true},
+			LevelError:     {Bold: true, Underline: true},
+			LevelCritical:  {Bold: true, Underline: true},
+			LevelAlert:     {Bold: true, Underline: true},
+			LevelFatal:     {Bold: true, Underline: true},
+			LevelEmergency: {Bold: true, Underline: true},
+			LevelPanic:     {Bold: true, Underline: true},
+			LevelPrint:     {},
+		},
+		Key:   Style{Dim: true},
+		Null:  Style{Italic: true},
+		Error: Style{Bold: true},
+	}
+)
+
+// themes is the registry used by LookupTheme. Access is protected by themesMu.
+var themes = map[string]Theme{
+	ThemeClassic.Name: ThemeClassic,
+	ThemeDark.Name:    ThemeDark,
+	ThemeLight.Name:   ThemeLight,
+	ThemeMono.Name:    ThemeMono,
+}
+
+var themesMu sync.RWMutex
+
+// RegisterTheme adds or replaces a named theme, making it available to LookupTheme and LOG_THEME.
+// This function is thread-safe.
+func RegisterTheme(theme Theme) {
+	themesMu.Lock()
+	defer themesMu.Unlock()
+	themes[strings.ToLower(theme.Name)] = theme
+}
+
+// LookupTheme returns the theme registered under name (case-insensitive).
+// This function is thread-safe.
+func LookupTheme(name string) (Theme, bool) {
+	themesMu.RLock()
+	defer themesMu.RUnlock()
+	theme, ok := themes[strings.ToLower(name)]
+	return theme, ok
+}
+
+// levelStyle returns the theme style for a level and whether one is defined.
+func (t *Theme) levelStyle(level Level) (Style, bool) {
+	style, ok := t.Levels[level]
+	return style, ok
+}
+
+// valueStyle returns the style for an encoded JSON field value, based on its type.
+func (t *Theme) valueStyle(encoded string) Style {
+	if encoded == "" {
+		return Style{}
+	}
+	switch c := encoded[0]; {
+	case c == '"':
+		return t.String
+	case c == 't' || c == 'f':
+		return t.Bool
+	case c == 'n':
+		return t.Null
+	case c == '-' || (c >= '0' && c <= '9'):
+		return t.Number
+	}
+	return Style{}
+}
@@ -0,0 +1,97 @@
+package logos
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConsoleFormatter_Theme(t *testing.T) {
+	theme := Theme{
+		Timestamp: Style{Dim: true},
+		Levels:    map[Level]Style{LevelInfo: {Fg: ANSIColor(2), Bold: true}},
+		Message:   Style{Bold: true},
+		Key:       Style{Fg: ANSIColor(6)},
+		String:    Style{Fg: ANSIColor(2)},
+		Number:    Style{Fg: ANSIColor(3)},
+		Bool:      Style{Fg: ANSIColor(5)},
+		Null:      Style{Italic: true},
+		Error:     Style{Fg: ANSIColor(1)},
+	}
+	cfg := Config{
+		Timestamp:    func() string { return "T" },
+		Theme:        &theme,
+		ColorProfile: ColorProfileANSI,
+	}
+
+	line := NewConsoleFormatter(cfg).Format(LevelInfo, Entry{
+		Msg:   "hello",
+		Error: errors.New("boom"),
+		Fields: Fields{
+			"s": "str",
+			"n": 42,
+			"b": true,
+			"z": nil,
+			"c": make(chan int),
+		},
+	})
+
+	parts := strings.Split(line, "\t")
+	if assert.Len(t, parts, 4) {
+		assert.Equal(t, "\033[2mT\033[0m", parts[0])
+		assert.Equal(t, "\033[1;32minfo\033[0m", parts[1])
+		assert.Equal(t, strings.Join([]string{
+			"\033[36mb\033[0m=\033[35mtrue\033[0m",
+			"\033[36mc\033[0m=\033[31m<marshal_error type=chan int: json: unsupported type: chan int>\033[0m",
+			"\033[36merror\033[0m=\033[31m\"boom\"\033[0m",
+			"\033[36mn\033[0m=\033[33m42\033[0m",
+			"\033[36ms\033[0m=\033[32m\"str\"\033[0m",
+			"\033[36mz\033[0m=\033[3mnull\033[0m",
+		}, " "), parts[2])
+		assert.Equal(t, "\033[1mhello\033[0m", parts[3])
+	}
+
+	// Levels without a theme style use the level colors.
+	line = NewConsoleFormatter(cfg).Format(LevelError, Entry{Msg: "hello"})
+	assert.Contains(t, line, string(ColorTextRed)+"error"+string(ColorReset))
+
+	// Nothing is colored without color support.
+	cfg.ColorProfile = ColorProfileNone
+	line = NewConsoleFormatter(cfg).Format(LevelError, Entry{Msg: "hello", Fields: Fields{"k": 1}})
+	assert.Equal(t, "T\terror\tk=1\thello", line)
+}
+
+func TestConsoleFormatter_ClassicTheme(t *testing.T) {
+	cfg := Config{Timestamp: func() string { return "T" }}
+	entry := Entry{Msg: "hello", Fields: Fields{"k": "v"}}
+	legacy := NewConsoleFormatter(cfg).Format(LevelInfo, entry)
+
+	theme, ok := LookupTheme("CLASSIC")
+	assert.True(t, ok)
+	cfg.Theme = &theme
+	cfg.ColorProfile = ColorProfileANSI
+	assert.Equal(t, legacy, NewConsoleFormatter(cfg).Format(LevelInfo, entry))
+}
+
+func TestRegisterTheme(t *testing.T) {
+	_, ok := LookupTheme("custom-test")
+	assert.False(t, ok)
+
+	RegisterTheme(Theme{Name: "Custom-Test", Key: Style{Bold: true}})
+	defer func() {
+		themesMu.Lock()
+		delete(themes, "custom-test")
+		themesMu.Unlock()
+	}()
+
+	theme, ok := LookupTheme("custom-test")
+	assert.True(t, ok)
+	assert.True(t, theme.Key.Bold)
+
+	for _, name := range []string{"classic", "dark", "light", "mono"} {
+		_, ok := LookupTheme(name)
+		assert.True(t, ok, name)
+	}
+}
@@ -0,0 +1,300 @@
+package logos
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+
+	"github.com/goodblaster/errors"
+)
+
+// W3C Trace Context header names.
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+)
+
+// TraceFlagSampled is the trace-flags bit set when the caller may have recorded the trace.
+const TraceFlagSampled byte = 0x01
+
+// maxTraceStateMembers is the number of list members a tracestate may carry.
+const maxTraceStateMembers = 32
+
+// CtxKeyTraceContext is the key used to store a TraceContext in context.Context.
+const CtxKeyTraceContext contextKey = "logos.trace_context"
+
+// TraceContext identifies the trace and span an entry belongs to, as carried by the
+// W3C Trace Context traceparent and tracestate headers. IDs are lower-case hex.
+type TraceContext struct {
+	TraceID    string // 32 hex characters.
+	SpanID     string // 16 hex characters: the parent-id of traceparent.
+	Flags      byte   // Trace flags; see TraceFlagSampled.
+	TraceState string // Vendor-specific tracestate list, passed along unchanged.
+}
+
+// NewTraceContext returns a TraceContext for a new trace with random IDs.
+func NewTraceContext(sampled bool) TraceContext {
+	tc := TraceContext{TraceID: randomHex(16), SpanID: randomHex(8)}
+	if sampled {
+		tc.Flags = TraceFlagSampled
+	}
+	return tc
+}
+
+// NewSpan returns a TraceContext for a child span: the same trace, flags and tracestate
+// with a new random span ID. Use it for the work done by a service receiving tc.
+func (tc TraceContext) NewSpan() TraceContext {
+	tc.SpanID = randomHex(8)
+	return tc
+}
+
+// Sampled reports whether the sampled flag is set.
+func (tc TraceContext) Sampled() bool {
+	return tc.Flags&TraceFlagSampled != 0
+}
+
+// IsValid reports whether tc has well-formed, non-zero trace and span IDs.
+func (tc TraceContext) IsValid() bool {
+	return validTraceID(tc.TraceID, 32) && validTraceID(tc.SpanID, 16)
+}
+
+// TraceParent returns the traceparent header value for tc, such as
+// "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01".
+func (tc TraceContext) TraceParent() string {
+	return "00-" + tc.TraceID + "-" + tc.SpanID + "-" + hex.EncodeToString([]byte{tc.Flags})
+}
+
+// WithTraceStateMember returns tc with key=value at the front of its tracestate, replacing
+// any previous value for key, as a vendor does when it updates the list. The last member is
+// dropped if the list would exceed the 32 members the specification allows.
+func (tc TraceContext) WithTraceStateMember(key, value string) (TraceContext, error) {
+	if !validTraceStateKey(key) {
+		return tc, errors.New("invalid tracestate key %q", key)
+	}
+	if !validTraceStateValue(value) {
+		return tc, errors.New("invalid tracestate value %q", value)
+	}
+
+	members := []string{key + "=" + value}
+	for _, member := range splitTraceState(tc.TraceState) {
+		if k, _, _ := strings.Cut(member, "="); k != key {
+			members = append(members, member)
+		}
+	}
+	if len(members) > maxTraceStateMembers {
+		members = members[:maxTraceStateMembers]
+	}
+	tc.TraceState = strings.Join(members, ",")
+	return tc, nil
+}
+
+// TraceStateMember returns the value of key in tc's tracestate.
+func (tc TraceContext) TraceStateMember(key string) (string, bool) {
+	for _, member := range splitTraceState(tc.TraceState) {
+		if k, v, ok := strings.Cut(member, "="); ok && k == key {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// Fields returns the trace_id, span_id and trace_sampled fields for tc.
+func (tc TraceContext) Fields() Fields {
+	return Fields{
+		FieldTraceID:      tc.TraceID,
+		FieldSpanID:       tc.SpanID,
+		FieldTraceSampled: tc.Sampled(),
+	}
+}
+
+// ParseTraceParent parses a traceparent header value. Versions above 00 are accepted as
+// long as they start with the version 00 layout, as the specification requires.
+func ParseTraceParent(traceparent string) (TraceContext, error) {
+	s := strings.TrimSpace(traceparent)
+	if len(s) < 55 || (len(s) > 55 && s[55] != '-') {
+		return TraceContext{}, errors.New("invalid traceparent %q", traceparent)
+	}
+	if s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return TraceContext{}, errors.New("invalid traceparent %q", traceparent)
+	}
+
+	version, err := parseHexByte(s[0:2])
+	if err != nil || version == 0xff || (version == 0 && len(s) != 55) {
+		return TraceContext{}, errors.New("invalid traceparent version in %q", traceparent)
+	}
+	flags, err := parseHexByte(s[53:55])
+	if err != nil {
+		return TraceContext{}, errors.New("invalid traceparent flags in %q", traceparent)
+	}
+
+	tc := TraceContext{TraceID: s[3:35], SpanID: s[36:52], Flags: flags}
+	if !tc.IsValid() {
+		return TraceContext{}, errors.New("invalid trace or span ID in %q", traceparent)
+	}
+	return tc, nil
+}
+
+// ParseTraceContext parses traceparent and tracestate header values. An invalid tracestate
+// is discarded rather than failing the parse, as the specification requires.
+func ParseTraceContext(traceparent, tracestate string) (TraceContext, error) {
+	tc, err := ParseTraceParent(traceparent)
+	if err != nil {
+		return tc, err
+	}
+	if validTraceState(tracestate) {
+		tc.TraceState = strings.Join(splitTraceState(tracestate), ",")
+	}
+	return tc, nil
+}
+
+// WithTraceContext returns a new context carrying tc. Loggers obtained with FromContext,
+// and entries logged with the *Context methods, then carry its trace fields.
+func WithTraceContext(ctx context.Context, tc TraceContext) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithValue(ctx, CtxKeyTraceContext, tc)
+}
+
+// TraceContextFromContext returns the TraceContext stored in the context, if any.
+func TraceContextFromContext(ctx context.Context) (TraceContext, bool) {
+	if ctx == nil {
+		return TraceContext{}, false
+	}
+	tc, ok := ctx.Value(CtxKeyTraceContext).(TraceContext)
+	return tc, ok && tc.IsValid()
+}
+
+// withTraceFields returns the logger with the context's trace fields added at the top level,
+// outside any group, so formatters can find them.
+func (logger Logger) withTraceFields(ctx context.Context) Logger {
+	tc, ok := TraceContextFromContext(ctx)
+	if !ok {
+		return logger
+	}
+	newLogger := logger.Copy()
+	if newLogger.fields == nil {
+		newLogger.fields = make(Fields, 3)
+	}
+	for key, value := range tc.Fields() {
+		newLogger.fields[key] = value
+	}
+	return newLogger
+}
+
+// validTraceID reports whether id is n lower-case hex characters and not all zeros.
+func validTraceID(id string, n int) bool {
+	if len(id) != n {
+		return false
+	}
+	zero := true
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+		if c != '0' {
+			zero = false
+		}
+	}
+	return !zero
+}
+
+// parseHexByte parses two lower-case hex characters.
+func parseHexByte(s string) (byte, error) {
+	if strings.ToLower(s) != s {
+		return 0, errors.New("hex must be lower-case: %q", s)
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid hex %q", s)
+	}
+	return b[0], nil
+}
+
+// splitTraceState returns the non-empty members of a tracestate list, trimmed.
+func splitTraceState(tracestate string) []string {
+	var members []string
+	for _, member := range strings.Split(tracestate, ",") {
+		if member = strings.TrimSpace(member); member != "" {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// validTraceState reports whether tracestate is a well-formed list without duplicate keys.
+func validTraceState(tracestate string) bool {
+	members := splitTraceState(tracestate)
+	if len(members) == 0 || len(members) > maxTraceStateMembers {
+		return false
+	}
+	seen := make(map[string]bool, len(members))
+	for _, member := range members {
+		key, value, ok := strings.Cut(member, "=")
+		if !ok || seen[key] || !validTraceStateKey(key) || !validTraceStateValue(value) {
+			return false
+		}
+		seen[key] = true
+	}
+	return true
+}
+
+// validTraceStateKey reports whether key is a simple key or a tenant@system multi-tenant key.
+func validTraceStateKey(key string) bool {
+	if key == "" || len(key) > 256 {
+		return false
+	}
+	if tenant, system, ok := strings.Cut(key, "@"); ok {
+		return tenant != "" && len(tenant) <= 241 && system != "" && len(system) <= 14 &&
+			validTraceStateKeyChars(tenant, true) && validTraceStateKeyChars(system, false)
+	}
+	return validTraceStateKeyChars(key, false)
+}
+
+func validTraceStateKeyChars(key string, tenant bool) bool {
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+		case c >= '0' && c <= '9':
+			if i == 0 && !tenant {
+				return false
+			}
+		case i > 0 && (c == '_' || c == '-' || c == '*' || c == '/'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// validTraceStateValue reports whether value is 1 to 256 printable ASCII characters other
+// than ',' and '=', not ending in a space.
+func validTraceStateValue(value string) bool {
+	if value == "" || len(value) > 256 || value[len(value)-1] == ' ' {
+		return false
+	}
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c < ' ' || c > '~' || c == ',' || c == '=' {
+			return false
+		}
+	}
+	return true
+}
+
+// randomHex returns n random bytes as hex, retrying in the unlikely case they are all zero.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	for {
+		if _, err := rand.Read(b); err != nil {
+			panic(errors.Wrap(err, "failed to generate trace ID"))
+		}
+		for _, c := range b {
+			if c != 0 {
+				return hex.EncodeToString(b)
+			}
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package logos
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestParseTraceParent(t *testing.T) {
+	tc, err := ParseTraceParent(testTraceParent)
+	assert.NoError(t, err)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", tc.TraceID)
+	assert.Equal(t, "00f067aa0ba902b7", tc.SpanID)
+	assert.True(t, tc.Sampled())
+	assert.Equal(t, testTraceParent, tc.TraceParent())
+
+	// Later versions may append fields after the version 00 layout
+	tc, err = ParseTraceParent("cc-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00-what-the-future-holds")
+	assert.NoError(t, err)
+	assert.False(t, tc.Sampled())
+
+	invalid := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473g-00f067aa0ba902b7-01",
+		"00_4bf92f3577b34da6a3ce929d0e0e4736_00f067aa0ba902b7_01",
+	}
+	for _, value := range invalid {
+		_, err := ParseTraceParent(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestParseTraceContext_TraceState(t *testing.T) {
+	tc, err := ParseTraceContext(testTraceParent, " congo=t61rcWkgMzE , rojo=00f067aa0ba902b7")
+	assert.NoError(t, err)
+	assert.Equal(t, "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7", tc.TraceState)
+	value, ok := tc.TraceStateMember("rojo")
+	assert.True(t, ok)
+	assert.Equal(t, "00f067aa0ba902b7", value)
+
+	// An invalid tracestate is dropped without failing the parse
+	tc, err = ParseTraceContext(testTraceParent, "congo=a,congo=b")
+	assert.NoError(t, err)
+	assert.Empty(t, tc.TraceState)
+}
+
+func TestTraceContext_WithTraceStateMember(t *testing.T) {
+	tc, err := ParseTraceContext(testTraceParent, "congo=t61rcWkgMzE,rojo=00f067aa0ba902b7")
+	assert.NoError(t, err)
+
+	tc, err = tc.WithTraceStateMember("rojo", "00f067aa0ba902b8")
+	assert.NoError(t, err)
+	assert.Equal(t, "rojo=00f067aa0ba902b8,congo=t61rcWkgMzE", tc.TraceState)
+
+	_, err = tc.WithTraceStateMember("Bad Key", "x")
+	assert.Error(t, err)
+	_, err = tc.WithTraceStateMember("ok", "a,b")
+	assert.Error(t, err)
+
+	for i := 0; i < 40; i++ {
+		tc, err = tc.WithTraceStateMember("k"+strings.Repeat("x", i), "v")
+		assert.NoError(t, err)
+	}
+	assert.Len(t, strings.Split(tc.TraceState, ","), 32)
+}
+
+func TestNewTraceContext(t *testing.T) {
+	tc := NewTraceContext(true)
+	assert.True(t, tc.IsValid())
+	assert.True(t, tc.Sampled())
+
+	parsed, err := ParseTraceParent(tc.TraceParent())
+	assert.NoError(t, err)
+	assert.Equal(t, tc, parsed)
+
+	child := tc.NewSpan()
+	assert.Equal(t, tc.TraceID, child.TraceID)
+	assert.NotEqual(t, tc.SpanID, child.SpanID)
+	assert.True(t, child.Sampled())
+}
+
+func TestFromContext_TraceFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tc, _ := ParseTraceParent(testTraceParent)
+	ctx := WithLogger(context.Background(), NewLogger(LevelInfo, JSONFormatter(), buf).WithGroup("req"))
+	ctx = WithTraceContext(ctx, tc)
+
+	FromContext(ctx).With("user", "alice").Info("hello")
+	m := Map(buf)
+	assert.Equal(t, tc.TraceID, m[FieldTraceID])
+	assert.Equal(t, tc.SpanID, m[FieldSpanID])
+	assert.Equal(t, true, m.Field(FieldTraceSampled)) // Not nested under the group
+	assert.Equal(t, map[string]any{"user": "alice"}, m.Field("req"))
+
+	// The *Context methods add the trace fields too
+	NewLogger(LevelInfo, JSONFormatter(), buf).InfoContext(ctx, "hello")
+	m = Map(buf)
+	assert.Equal(t, tc.TraceID, m[FieldTraceID])
+
+	// Invalid trace contexts are ignored
+	ctx = WithTraceContext(context.Background(), TraceContext{TraceID: "abc"})
+	assert.Nil(t, ContextFields(ctx))
+}
+
+func TestJSONFormatter_TraceKeys(t *testing.T) {
+	entry := Entry{Msg: "Test", Fields: Fields{FieldTraceID: "t1", FieldSpanID: "s1", "user": "alice"}}
+
+	line := NewJsonFormatter(DefaultConfig).Format(LevelInfo, entry)
+	assert.Contains(t, line, `"trace_id":"t1","span_id":"s1","fields":{"user":"alice"}`)
+
+	cfg := DefaultConfig
+	cfg.JSON = JSONConfig{TraceIDKey: "trace.id", SpanIDKey: "span.id", FlattenFields: true}
+	line = NewJsonFormatter(cfg).Format(LevelInfo, entry)
+	assert.Contains(t, line, `"trace.id":"t1","span.id":"s1","user":"alice"`)
+}
@@ -0,0 +1,25 @@
+package logos
+
+// Transformer rewrites a log entry after it passes the level check and before it is formatted.
+// Transformers must not modify the entry's Fields map in place, since it is shared with the
+// logger; they return an entry with a new map instead.
+type Transformer interface {
+	Transform(level Level, entry Entry) Entry
+}
+
+// TransformerFunc adapts a function to the Transformer interface.
+type TransformerFunc func(level Level, entry Entry) Entry
+
+// Transform calls f(level, entry).
+func (f TransformerFunc) Transform(level Level, entry Entry) Entry {
+	return f(level, entry)
+}
+
+// WithTransformer returns a new Logger that applies t to every entry before formatting it.
+// Transformers also apply to the logger's tee loggers, including those added later,
+// so nothing reaches any destination without passing through them.
+func (logger Logger) WithTransformer(t Transformer) Logger {
+	newLogger := logger.Copy()
+	newLogger.transformers = append(newLogger.transformers, t)
+	return newLogger
+}
@@ -0,0 +1,50 @@
+package logos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogger_WithTransformer(t *testing.T) {
+	buf := &bytes.Buffer{}
+	var calls int
+	upper := TransformerFunc(func(level Level, entry Entry) Entry {
+		calls++
+		entry.Msg = entry.Msg + "!"
+		return entry
+	})
+
+	base := NewLogger(LevelInfo, JSONFormatter(), buf)
+	log := base.WithTransformer(upper).WithTransformer(upper)
+
+	log.Info("hello")
+	assert.Equal(t, "hello!!", Map(buf)["msg"])
+
+	// Transformers only run for entries that pass the level check.
+	log.Debug("filtered")
+	assert.Equal(t, 2, calls)
+
+	// The original logger is unaffected.
+	base.Info("hello")
+	assert.Equal(t, "hello", Map(buf)["msg"])
+}
+
+func TestLogger_WithTransformer_Tees(t *testing.T) {
+	buf1 := &bytes.Buffer{}
+	buf2 := &bytes.Buffer{}
+	suffix := func(s string) Transformer {
+		return TransformerFunc(func(level Level, entry Entry) Entry {
+			entry.Msg += s
+			return entry
+		})
+	}
+
+	tee := NewLogger(LevelInfo, JSONFormatter(), buf2).WithTransformer(suffix("-tee"))
+	log := NewLogger(LevelInfo, JSONFormatter(), buf1).WithTransformer(suffix("-main")).Tee(tee)
+
+	log.Info("msg")
+	assert.Equal(t, "msg-main", Map(buf1)["msg"])
+	assert.Equal(t, "msg-main-tee", Map(buf2)["msg"])
+}